@@ -0,0 +1,92 @@
+// Package main. Агент узла gorchester.
+// Регистрирует узел в control plane, шлёт heartbeat и выполняет команды на локальном Docker.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/exitae337/gorchester/internal/agent"
+	"github.com/exitae337/gorchester/internal/client"
+)
+
+func main() {
+	hostname, _ := os.Hostname()
+
+	controlPlane := flag.String("control-plane", "http://localhost:8080", "Control plane API address")
+	joinToken := flag.String("join-token", os.Getenv("GORCHESTER_JOIN_TOKEN"), "Cluster join token (env GORCHESTER_JOIN_TOKEN)")
+	nodeID := flag.String("node-id", hostname, "Node ID in cluster")
+	nodeHostname := flag.String("hostname", hostname, "Node hostname")
+	ip := flag.String("ip", "", "Node address")
+	cpu := flag.Int64("cpu", 0, "CPU capacity in millicores (0 = all cores)")
+	memory := flag.Int64("memory", 0, "Memory capacity in bytes (0 = total memory)")
+	disk := flag.Int64("disk", 0, "Disk capacity in bytes (0 = size of --disk-path filesystem)")
+	diskPath := flag.String("disk-path", "/", "Filesystem for disk usage")
+	labels := flag.String("labels", "", "Node labels: key=value,key=value")
+	debug := flag.Bool("debug", false, "Debug logs")
+	flag.Parse()
+
+	level := slog.LevelInfo
+	if *debug {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+
+	nodeLabels, err := parseLabels(*labels)
+	if err != nil {
+		log.Fatalf("invalid labels: %v", err)
+	}
+
+	dockerClient, err := client.NewDockerClient()
+	if err != nil {
+		logger.Error("failed to create docker client", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer dockerClient.Close()
+
+	a := agent.New(agent.Config{
+		ControlPlane: *controlPlane,
+		JoinToken:    *joinToken,
+		NodeID:       *nodeID,
+		Hostname:     *nodeHostname,
+		IP:           *ip,
+		CPU:          *cpu,
+		Memory:       *memory,
+		Disk:         *disk,
+		DiskPath:     *diskPath,
+		Labels:       nodeLabels,
+	}, dockerClient, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("agent starting", "node_id", *nodeID, "control_plane", *controlPlane)
+	if err := a.Run(ctx); err != nil && ctx.Err() == nil {
+		logger.Error("agent stopped with error", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger.Info("agent stopped")
+}
+
+// parseLabels -> "zone=a,disk=ssd" to map
+func parseLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	if s == "" {
+		return labels, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
@@ -8,15 +8,21 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
+	"github.com/exitae337/gorchester/internal/agent"
 	"github.com/exitae337/gorchester/internal/api"
 	"github.com/exitae337/gorchester/internal/client"
 	"github.com/exitae337/gorchester/internal/config"
 	"github.com/exitae337/gorchester/internal/core"
+	"github.com/exitae337/gorchester/internal/dns"
+	"github.com/exitae337/gorchester/internal/ingress"
 	"github.com/exitae337/gorchester/internal/scheduler"
+	"github.com/exitae337/gorchester/internal/secrets"
 	"github.com/exitae337/gorchester/internal/store"
+	"github.com/exitae337/gorchester/internal/types"
 )
 
 const (
@@ -26,6 +32,11 @@ const (
 )
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		os.Exit(runSimulate(os.Args[2:]))
+	}
+
 	// Loading configuration file for orchestration process
 	cfg := config.MustLoad()
 	// Init Logger
@@ -33,37 +44,91 @@ func main() {
 	if logger == nil {
 		log.Fatalf("env string must be: local, dev or prod")
 	}
+
+	// Secrets store -> values are hidden in every log line from here on
+	secretsKey, generated, err := secrets.LoadKey(cfg.Secrets)
+	if err != nil {
+		logger.Error("failed to load secrets master key", slog.Any("error", err))
+		os.Exit(1)
+	}
+	secretStore, err := secrets.Open(filepath.Join(cfg.DataDir, "secrets"), secretsKey)
+	if err != nil {
+		logger.Error("failed to open secrets store", slog.Any("error", err))
+		os.Exit(1)
+	}
+	logger = slog.New(secrets.NewRedactHandler(logger.Handler(), secretStore))
+	if generated {
+		logger.Warn("secrets master key generated, keep a copy apart from data_dir", "key_file", cfg.Secrets.KeyFile)
+	}
+
 	logger.Info("starting orchestrator", slog.String("env", cfg.Env))
 	logger.Debug("debug messages are enabled")
 	// Make components
 	taskStore := store.New()
+	volumeStore := store.NewVolumeStore()
 	logger.Info("in-memory task store initialized")
 
-	// Docker Client
+	// Docker Client -> local daemon is required only for nodes without docker endpoint
+	var local client.ContainerManager
 	dockerClient, err := client.NewDockerClient()
 	if err != nil {
-		logger.Error("failed to create docker client", slog.Any("error", err))
-		os.Exit(1)
+		if !allNodesRemote(cfg) {
+			logger.Error("failed to create docker client", slog.Any("error", err))
+			os.Exit(1)
+		}
+		logger.Warn("local docker daemon unavailable, all nodes use remote endpoints", slog.Any("error", err))
+	} else {
+		local = dockerClient
+		logger.Info("docker client connected")
 	}
-	defer dockerClient.Close()
-	logger.Info("docker client connected")
+	runtimes := client.NewPool(local, logger)
+	defer runtimes.Close()
 
 	schedulerConfig := scheduler.DefaultConfig()
 	schedulerConfig.Strategy = scheduler.StrategySpread
+	schedulerConfig.PortRangeStart = cfg.HostPortRange.Start
+	schedulerConfig.PortRangeEnd = cfg.HostPortRange.End
+	schedulerConfig.Overcommit = cfg.Overcommit
 	sched := scheduler.New(schedulerConfig, logger, cfg.Nodes, taskStore)
 	defer sched.Stop()
 
 	logger.Info("scheduler created", "strategy", schedulerConfig.Strategy)
 
+	// Remote nodes -> own Docker endpoint, unreachable node is not schedulable
+	for _, node := range cfg.Nodes {
+		if !node.Docker.IsRemote() {
+			continue
+		}
+		if err := runtimes.AddNode(node.ID, node.Docker); err != nil {
+			logger.Error("failed to connect to node docker endpoint",
+				"node_id", node.ID,
+				"host", node.Docker.Host,
+				"error", err)
+			sched.UpdateNodeStatus(context.Background(), node.ID, types.NodeStatusNotReady)
+			continue
+		}
+		logger.Info("node docker endpoint connected", "node_id", node.ID, "host", node.Docker.Host)
+	}
+
 	// Orchestrator object
 	orch := core.New(
 		cfg,
 		taskStore,
-		dockerClient,
+		volumeStore,
+		runtimes,
 		sched,
 		logger,
 	)
 
+	orch.SetSecrets(secretStore)
+
+	// Ingress proxy -> gets task events from orchestrator
+	var proxy *ingress.Proxy
+	if cfg.Ingress.Enabled {
+		proxy = ingress.New(cfg.Ingress, orch, logger)
+		orch.SetBalancer(proxy)
+	}
+
 	if err := orch.Start(); err != nil {
 		logger.Error("failed to start orchestrator", "error", err)
 		os.Exit(1)
@@ -72,7 +137,12 @@ func main() {
 	logger.Info("orchestrtor started!")
 
 	// Create and start API Server
-	apiServer := api.NewAPIServer(orch, sched, orch.GetMetricsStore(), logger)
+	agents := agent.NewHub(sched, runtimes, logger)
+	apiServer := api.NewAPIServer(orch, sched, orch.GetMetricsStore(), agents, api.Tokens{
+		Join:    cfg.JoinToken,
+		Admin:   cfg.AdminToken,
+		DataDir: cfg.DataDir,
+	}, logger)
 	go func() {
 		logger.Info("API server starting", "addr", cfg.ListenAddr)
 		if err := apiServer.Start(cfg.ListenAddr); err != nil {
@@ -80,6 +150,23 @@ func main() {
 		}
 	}()
 
+	// Service discovery DNS
+	var dnsServer *dns.Server
+	if cfg.DNS.Enabled {
+		dnsServer = dns.NewServer(cfg.DNS, cfg.ClusterName, orch, logger)
+		go func() {
+			if err := dnsServer.ListenAndServe(cfg.DNS.ListenAddr); err != nil {
+				logger.Error("DNS server failed", "error", err)
+			}
+		}()
+	}
+
+	if proxy != nil {
+		if err := proxy.Start(); err != nil {
+			logger.Error("ingress proxy failed", "error", err)
+		}
+	}
+
 	// Print info about Orchestartor
 	printServiceStatus(context.Background(), orch, logger)
 
@@ -90,6 +177,13 @@ func main() {
 
 	logger.Info("shutting down...")
 
+	if dnsServer != nil {
+		dnsServer.Close()
+	}
+	if proxy != nil {
+		proxy.Close()
+	}
+
 	// Stop orchestrator
 	if err := orch.Stop(); err != nil {
 		logger.Error("failed to stop orchestartor", slog.Any("error", err))
@@ -98,8 +192,8 @@ func main() {
 	// Stop scheduler
 	sched.Stop()
 
-	if err := dockerClient.Close(); err != nil {
-		logger.Error("failed to close docker client", slog.Any("error", err))
+	if err := runtimes.Close(); err != nil {
+		logger.Error("failed to close docker clients", slog.Any("error", err))
 	}
 
 	logger.Info("orchestrator stopped")
@@ -146,6 +240,19 @@ func printServiceStatus(ctx context.Context, orch *core.Orchestrator, logger *sl
 	}
 }
 
+// allNodesRemote -> every node has own Docker endpoint
+func allNodesRemote(cfg *types.OchestratorConfig) bool {
+	if len(cfg.Nodes) == 0 {
+		return false
+	}
+	for _, node := range cfg.Nodes {
+		if !node.Docker.IsRemote() {
+			return false
+		}
+	}
+	return true
+}
+
 func setupLogger(env string) *slog.Logger {
 	var logger *slog.Logger
 	switch env {
@@ -0,0 +1,123 @@
+// Package main. simulate.go -> команда "gorchester simulate":
+// размещение конфигурации без Docker и сравнение стратегий планировщика.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/exitae337/gorchester/internal/config"
+	"github.com/exitae337/gorchester/internal/scheduler"
+	"github.com/exitae337/gorchester/internal/simulator"
+)
+
+// runSimulate -> "gorchester simulate --config X [--strategy S] [--format text|json]"
+func runSimulate(args []string) int {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	configPath := fs.String("config", "config/config.yaml", "Path to configuration file")
+	strategy := fs.String("strategy", string(scheduler.StrategySpread), "Strategy for the detailed report")
+	format := fs.String("format", "text", "Output format: text, json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	reports := make([]*simulator.Report, 0, len(scheduler.Strategies))
+	var detailed *simulator.Report
+	for _, s := range scheduler.Strategies {
+		report, err := simulator.Run(ctx, cfg, s)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "simulation with %s failed: %v\n", s, err)
+			return 1
+		}
+		reports = append(reports, report)
+		if string(s) == *strategy {
+			detailed = report
+		}
+	}
+
+	if detailed == nil {
+		fmt.Fprintf(os.Stderr, "unknown strategy: %s\n", *strategy)
+		return 2
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to encode reports: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	printReport(detailed)
+	printComparison(reports)
+	return 0
+}
+
+// printReport -> placement, utilization, unschedulable replicas and violations
+func printReport(r *simulator.Report) {
+	fmt.Printf("Simulation (strategy: %s)\n\n", r.Strategy)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TASK\tSERVICE\tNODE")
+	for _, p := range r.Placements {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.TaskID, p.Service, p.NodeID)
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tTASKS\tCPU\tCPU %\tMEMORY MB\tMEM %")
+	for _, n := range r.Nodes {
+		fmt.Fprintf(w, "%s\t%d\t%d/%dm\t%.1f\t%d/%d\t%.1f\n",
+			n.NodeID, n.Tasks,
+			n.CPUUsed, n.CPUCapacity, n.CPUPercent,
+			n.MemUsed/1024/1024, n.MemCapacity/1024/1024, n.MemPercent)
+	}
+	w.Flush()
+
+	if len(r.Unschedulable) > 0 {
+		fmt.Printf("\nUnschedulable replicas: %d\n", len(r.Unschedulable))
+		for _, u := range r.Unschedulable {
+			fmt.Printf("  %s #%d: %s\n", u.Service, u.Replica, u.Reason)
+		}
+	}
+
+	if len(r.Violations) > 0 {
+		fmt.Printf("\nConstraint violations: %d\n", len(r.Violations))
+		for _, v := range r.Violations {
+			kind := "hard"
+			if v.Soft {
+				kind = "soft"
+			}
+			fmt.Printf("  [%s] %s %s %s: %s\n", kind, v.Service, v.TaskID, v.NodeID, v.Rule)
+		}
+	}
+}
+
+// printComparison -> all strategies side by side
+func printComparison(reports []*simulator.Report) {
+	fmt.Printf("\nStrategies comparison\n\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STRATEGY\tPLACED\tUNSCHEDULABLE\tVIOLATIONS\tNODES USED\tMAX CPU %\tMAX MEM %")
+	for _, r := range reports {
+		maxCPU, maxMem := r.MaxUtilization()
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.1f\t%.1f\n",
+			r.Strategy, len(r.Placements), len(r.Unschedulable), len(r.Violations),
+			r.NodesUsed(), maxCPU, maxMem)
+	}
+	w.Flush()
+}
@@ -62,11 +62,14 @@ func printConfigText(cfg *types.OchestratorConfig) {
 		fmt.Printf("\n  [%d] %s\n", i+1, service.ServiceName)
 		fmt.Printf("      Image: %s\n", service.Image)
 		fmt.Printf("      Replicas: %d\n", service.Replicas)
-		fmt.Printf("      CPU: %dm (%0.2f cores)\n",
-			service.Resources.CPUMilliCores,
-			float64(service.Resources.CPUMilliCores)/1000)
-		fmt.Printf("      Memory: %d MB\n",
-			service.Resources.MemoryBytes/(1024*1024))
+		fmt.Printf("      CPU: request %dm (%0.2f cores), limit %dm\n",
+			service.Resources.CPURequest(),
+			float64(service.Resources.CPURequest())/1000,
+			service.Resources.CPULimit())
+		fmt.Printf("      Memory: request %d MB, limit %d MB\n",
+			service.Resources.MemoryRequest()/(1024*1024),
+			service.Resources.MemoryLimit()/(1024*1024))
+		fmt.Printf("      QoS: %s\n", service.Resources.QoSClass())
 
 		// Open ports
 		if len(service.Ports) > 0 {
@@ -0,0 +1,528 @@
+// Package agent. agent.go -> сторона узла: регистрация в control plane,
+// heartbeat с реальной загрузкой и контейнерами, выполнение команд на локальном Docker.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/client"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+const (
+	// pause after failed request to control plane
+	retryMin = 1 * time.Second
+	retryMax = 30 * time.Second
+)
+
+// errNodeUnknown -> control plane does not know node (restarted), register again
+var errNodeUnknown = errors.New("node is not registered")
+
+// Config -> agent settings
+type Config struct {
+	ControlPlane string            // control plane API address, http://10.0.0.1:8080
+	JoinToken    string            // cluster join token for registration
+	NodeID       string            // node ID in cluster
+	Hostname     string            // node hostname
+	IP           string            // node address
+	CPU          int64             // in millicores, 0 -> all cores
+	Memory       int64             // in bytes, 0 -> MemTotal
+	Disk         int64             // in bytes, 0 -> size of DiskPath filesystem
+	DiskPath     string            // filesystem for disk usage ("/" by default)
+	Labels       map[string]string // node labels
+}
+
+// Agent -> gorchester node agent
+type Agent struct {
+	cfg    Config
+	docker *client.DockerClient
+	http   *http.Client
+	logger *slog.Logger
+
+	// set by register, heartbeat may register again while commands are polled
+	timingMu          sync.RWMutex
+	heartbeatInterval time.Duration
+	pollWait          time.Duration
+
+	// issued by control plane on register
+	tokenMu   sync.RWMutex
+	nodeToken string
+
+	// previous /proc/stat sample
+	cpuMu     sync.Mutex
+	prevIdle  uint64
+	prevTotal uint64
+}
+
+// New -> agent for local Docker daemon
+func New(cfg Config, docker *client.DockerClient, logger *slog.Logger) *Agent {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.DiskPath == "" {
+		cfg.DiskPath = "/"
+	}
+	cfg.ControlPlane = strings.TrimRight(cfg.ControlPlane, "/")
+
+	return &Agent{
+		cfg:               cfg,
+		docker:            docker,
+		http:              &http.Client{},
+		logger:            logger.With("component", "agent", "node_id", cfg.NodeID),
+		heartbeatInterval: DefaultHeartbeatInterval,
+		pollWait:          DefaultPollWait,
+	}
+}
+
+// Run -> register, then heartbeats and commands until ctx is done
+func (a *Agent) Run(ctx context.Context) error {
+	if err := a.registerWithRetry(ctx); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		a.heartbeatLoop(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		a.commandLoop(ctx)
+	}()
+	wg.Wait()
+
+	return nil
+}
+
+// registerWithRetry -> register until control plane accepts node
+func (a *Agent) registerWithRetry(ctx context.Context) error {
+	backoff := retryMin
+	for {
+		err := a.register(ctx)
+		if err == nil {
+			return nil
+		}
+
+		a.logger.Warn("failed to register node, retrying",
+			"control_plane", a.cfg.ControlPlane,
+			"retry_in", backoff,
+			"error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, retryMax)
+	}
+}
+
+// register -> join cluster with detected capacity
+func (a *Agent) register(ctx context.Context) error {
+	req := RegisterRequest{
+		NodeID:   a.cfg.NodeID,
+		Hostname: a.cfg.Hostname,
+		IP:       a.cfg.IP,
+		CPU:      a.cfg.CPU,
+		Memory:   a.cfg.Memory,
+		Disk:     a.cfg.Disk,
+		Labels:   a.cfg.Labels,
+	}
+	a.tokenMu.RLock()
+	req.NodeToken = a.nodeToken
+	a.tokenMu.RUnlock()
+
+	if req.CPU == 0 {
+		req.CPU = int64(runtime.NumCPU()) * 1000
+	}
+	if req.Memory == 0 {
+		if total, _, err := memoryInfo(); err == nil {
+			req.Memory = total
+		}
+	}
+	if req.Disk == 0 {
+		if total, _, err := diskInfo(a.cfg.DiskPath); err == nil {
+			req.Disk = total
+		}
+	}
+
+	var resp RegisterResponse
+	if err := a.postJSON(ctx, PathRegister, req, &resp); err != nil {
+		return err
+	}
+
+	a.timingMu.Lock()
+	if resp.HeartbeatInterval > 0 {
+		a.heartbeatInterval = time.Duration(resp.HeartbeatInterval) * time.Second
+	}
+	if resp.PollWait > 0 {
+		a.pollWait = time.Duration(resp.PollWait) * time.Second
+	}
+	heartbeatInterval := a.heartbeatInterval
+	a.timingMu.Unlock()
+	a.tokenMu.Lock()
+	a.nodeToken = resp.NodeToken
+	a.tokenMu.Unlock()
+
+	a.logger.Info("node registered",
+		"control_plane", a.cfg.ControlPlane,
+		"cpu", req.CPU,
+		"memory", req.Memory,
+		"disk", req.Disk,
+		"heartbeat_interval", heartbeatInterval)
+	return nil
+}
+
+// timings -> heartbeat interval and long-poll wait issued on last register
+func (a *Agent) timings() (heartbeatInterval, pollWait time.Duration) {
+	a.timingMu.RLock()
+	defer a.timingMu.RUnlock()
+	return a.heartbeatInterval, a.pollWait
+}
+
+// heartbeatLoop -> report usage every heartbeat interval
+func (a *Agent) heartbeatLoop(ctx context.Context) {
+	interval, _ := a.timings()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if a.sendHeartbeat(ctx) {
+			// control plane may have issued another interval on register
+			interval, _ = a.timings()
+			ticker.Reset(interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendHeartbeat -> one heartbeat, register again when control plane forgot node (true if registered)
+func (a *Agent) sendHeartbeat(ctx context.Context) bool {
+	hb := Heartbeat{
+		NodeID: a.cfg.NodeID,
+		Report: a.collectReport(ctx),
+	}
+
+	err := a.postJSON(ctx, PathHeartbeat, hb, nil)
+	if errors.Is(err, errNodeUnknown) {
+		a.logger.Warn("control plane does not know node, registering again")
+		if err = a.register(ctx); err == nil {
+			return true
+		}
+	}
+	if err != nil && ctx.Err() == nil {
+		a.logger.Warn("failed to send heartbeat", "error", err)
+	}
+	return false
+}
+
+// collectReport -> real CPU, memory and disk usage, gorchester containers
+func (a *Agent) collectReport(ctx context.Context) types.NodeReport {
+	report := types.NodeReport{
+		Containers: make([]types.ReportedContainer, 0),
+	}
+
+	if idle, total, err := readCPUTimes(); err == nil {
+		a.cpuMu.Lock()
+		if deltaTotal := total - a.prevTotal; a.prevTotal > 0 && deltaTotal > 0 {
+			busy := 1 - float64(idle-a.prevIdle)/float64(deltaTotal)
+			report.CPUUsed = int64(busy * float64(runtime.NumCPU()) * 1000)
+		}
+		a.prevIdle, a.prevTotal = idle, total
+		a.cpuMu.Unlock()
+	} else {
+		a.logger.Debug("failed to read cpu usage", "error", err)
+	}
+
+	if _, used, err := memoryInfo(); err == nil {
+		report.MemoryUsed = used
+	} else {
+		a.logger.Debug("failed to read memory usage", "error", err)
+	}
+
+	if _, used, err := diskInfo(a.cfg.DiskPath); err == nil {
+		report.DiskUsed = used
+	} else {
+		a.logger.Debug("failed to read disk usage", "error", err)
+	}
+
+	containers, err := a.docker.ListContainers(ctx, map[string]string{"managed-by": "gorchester"})
+	if err != nil {
+		a.logger.Warn("failed to list containers", "error", err)
+		return report
+	}
+	for _, c := range containers {
+		report.Containers = append(report.Containers, types.ReportedContainer{
+			ID:     c.ID,
+			TaskID: c.Labels["gorchester.task_id"],
+			State:  c.State,
+		})
+	}
+	return report
+}
+
+// commandLoop -> long-poll commands and execute them concurrently
+func (a *Agent) commandLoop(ctx context.Context) {
+	backoff := retryMin
+	for ctx.Err() == nil {
+		commands, err := a.pollCommands(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			a.logger.Warn("failed to poll commands", "retry_in", backoff, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, retryMax)
+			continue
+		}
+		backoff = retryMin
+
+		for _, cmd := range commands {
+			go a.execute(ctx, cmd)
+		}
+	}
+}
+
+// pollCommands -> commands queued for node (request is held by control plane)
+func (a *Agent) pollCommands(ctx context.Context) ([]Command, error) {
+	_, pollWait := a.timings()
+	ctx, cancel := context.WithTimeout(ctx, pollWait+10*time.Second)
+	defer cancel()
+
+	path := PathCommands + "?node_id=" + url.QueryEscape(a.cfg.NodeID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.cfg.ControlPlane+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	a.authorize(req, PathCommands)
+
+	var commands []Command
+	if err := a.do(req, &commands); err != nil {
+		return nil, err
+	}
+	return commands, nil
+}
+
+// execute -> run command on local Docker and send result back
+func (a *Agent) execute(ctx context.Context, cmd Command) {
+	if time.Now().After(cmd.Deadline) {
+		a.logger.Debug("command expired, skipping", "command_id", cmd.ID, "op", cmd.Op)
+		return
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, cmd.Deadline)
+	defer cancel()
+
+	result := CommandResult{ID: cmd.ID, NodeID: a.cfg.NodeID}
+	var err error
+
+	switch cmd.Op {
+	case OpCreate:
+		if cmd.Task == nil {
+			err = fmt.Errorf("create command without task")
+			break
+		}
+		for i := range cmd.Task.Secrets {
+			cmd.Task.Secrets[i].Value = cmd.Secrets[cmd.Task.Secrets[i].Name]
+		}
+		result.Value, err = a.docker.CreateContainer(ctx, cmd.Task, a.logger)
+	case OpStart:
+		err = a.docker.StartContainer(ctx, cmd.ContainerID)
+	case OpStop:
+		err = a.docker.StopContainer(ctx, cmd.ContainerID)
+	case OpUpdateCPUSet:
+		err = a.docker.UpdateContainerCPUSet(ctx, cmd.ContainerID, cmd.CPUSet)
+	case OpRemove:
+		err = a.docker.RemoveContainer(ctx, cmd.ContainerID)
+	case OpStatus:
+		result.Value, err = a.docker.GetConatinerStatus(ctx, cmd.ContainerID)
+	case OpHealth:
+		result.Healthy, err = a.docker.CheckContainerHealth(ctx, cmd.ContainerID, cmd.HealthCheck)
+	case OpDiskUsage:
+		result.Size, err = a.docker.GetContainerDiskUsage(ctx, cmd.ContainerID)
+	case OpDisconnect:
+		err = a.docker.DisconnectFromNetwork(ctx, cmd.ContainerID)
+	case OpPull:
+		err = a.docker.PullImage(ctx, cmd.Image, a.logger)
+	case OpList:
+		result.Containers, err = a.docker.ListContainers(ctx, cmd.Filters)
+	case OpRemoveVolume:
+		err = a.docker.RemoveVolume(ctx, cmd.Volume)
+	case OpExportVolume:
+		err = a.uploadArchive(ctx, cmd.ID, func(w io.Writer) error {
+			return a.docker.ExportVolume(ctx, cmd.Volume, cmd.Image, w)
+		})
+	case OpImportVolume:
+		if cmd.VolumeSpec == nil {
+			err = fmt.Errorf("import command without volume")
+			break
+		}
+		err = a.downloadArchive(ctx, cmd.ID, func(r io.Reader) error {
+			return a.docker.ImportVolume(ctx, *cmd.VolumeSpec, cmd.Image, r)
+		})
+	case OpExec:
+		result.Value, err = a.docker.ExecCommand(ctx, cmd.ContainerID, cmd.Cmd)
+	case OpListNetworks:
+		result.Networks, err = a.docker.ListNetworks(ctx)
+	case OpRemoveNetwork:
+		err = a.docker.RemoveNetwork(ctx, cmd.Network)
+	default:
+		err = fmt.Errorf("unknown command: %s", cmd.Op)
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+		a.logger.Debug("command failed", "command_id", cmd.ID, "op", cmd.Op, "error", err)
+	}
+
+	if err := a.postJSON(context.Background(), PathResults, result, nil); err != nil {
+		a.logger.Warn("failed to send command result",
+			"command_id", cmd.ID,
+			"op", cmd.Op,
+			"error", err)
+	}
+}
+
+// postJSON -> POST body to control plane, decode answer into out (may be nil)
+func (a *Agent) postJSON(ctx context.Context, path string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.cfg.ControlPlane+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	a.authorize(req, path)
+	return a.do(req, out)
+}
+
+// uploadArchive -> archive written by export is streamed to control plane, not buffered
+func (a *Agent) uploadArchive(ctx context.Context, commandID string, export func(io.Writer) error) error {
+	pr, pw := io.Pipe()
+	exported := make(chan error, 1)
+	go func() {
+		err := export(pw)
+		pw.CloseWithError(err)
+		exported <- err
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.archiveURL(commandID), pr)
+	if err != nil {
+		pr.CloseWithError(err)
+		<-exported
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-tar")
+	a.authorize(req, PathArchive)
+
+	uploadErr := a.do(req, nil)
+	// Upload stopped early -> export is not left blocked on pipe
+	pr.CloseWithError(uploadErr)
+	if err := <-exported; err != nil {
+		return err
+	}
+	return uploadErr
+}
+
+// downloadArchive -> archive from control plane is streamed into import
+func (a *Agent) downloadArchive(ctx context.Context, commandID string, importArchive func(io.Reader) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.archiveURL(commandID), nil)
+	if err != nil {
+		return err
+	}
+	a.authorize(req, PathArchive)
+
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(req, resp); err != nil {
+		return err
+	}
+	return importArchive(resp.Body)
+}
+
+func (a *Agent) archiveURL(commandID string) string {
+	return a.cfg.ControlPlane + PathArchive +
+		"?node_id=" + url.QueryEscape(a.cfg.NodeID) +
+		"&command_id=" + url.QueryEscape(commandID)
+}
+
+// authorize -> join token for register, node token for everything else
+func (a *Agent) authorize(req *http.Request, path string) {
+	token := a.cfg.JoinToken
+	if path != PathRegister {
+		a.tokenMu.RLock()
+		token = a.nodeToken
+		a.tokenMu.RUnlock()
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// do -> send request, API errors come as {"error": "..."}
+func (a *Agent) do(req *http.Request, out interface{}) error {
+	resp, err := a.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(req, resp); err != nil {
+		return err
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// checkResponse -> error of failed request, unknown node or token -> errNodeUnknown
+func checkResponse(req *http.Request, resp *http.Response) error {
+	// Node or its token is unknown (control plane restarted, node removed) -> register again
+	if resp.StatusCode == http.StatusNotFound ||
+		(resp.StatusCode == http.StatusUnauthorized && req.URL.Path != PathRegister) {
+		return errNodeUnknown
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+			return fmt.Errorf("control plane: %s (%d)", apiErr.Error, resp.StatusCode)
+		}
+		return fmt.Errorf("control plane: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Package agent. archive.go -> архивы томов между control plane и агентом:
+// идут потоком через отдельный эндпоинт, а не в JSON команды, и целиком в памяти не держатся.
+package agent
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrNoArchive -> no export or import command of node waits for archive
+	ErrNoArchive = errors.New("no archive transfer for command")
+
+	errArchiveClosed = errors.New("archive transfer is closed")
+)
+
+// archiveTransfer -> archive of one export or import command
+type archiveTransfer struct {
+	nodeID string
+	w      io.Writer // export: archive from agent goes here
+	r      io.Reader // import: archive for agent comes from here
+
+	mu      sync.Mutex
+	claimed bool
+	closed  bool
+	done    chan struct{} // closed when copy is finished
+	err     error
+}
+
+func newArchiveTransfer(nodeID string, w io.Writer, r io.Reader) *archiveTransfer {
+	return &archiveTransfer{nodeID: nodeID, w: w, r: r, done: make(chan struct{})}
+}
+
+// Write -> no writes into caller writer after transfer is closed
+func (t *archiveTransfer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return 0, errArchiveClosed
+	}
+	return t.w.Write(p)
+}
+
+// Read -> no reads from caller reader after transfer is closed
+func (t *archiveTransfer) Read(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return 0, errArchiveClosed
+	}
+	return t.r.Read(p)
+}
+
+// close -> caller is done, returns after copy in progress stops touching its writer or reader
+func (t *archiveTransfer) close() {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+}
+
+// finished -> archive was copied completely (nil) or why not
+func (t *archiveTransfer) finished() error {
+	select {
+	case <-t.done:
+		return t.err
+	default:
+		return errors.New("agent did not transfer volume archive")
+	}
+}
+
+// openArchive -> command waits for archive transfer
+func (h *Hub) openArchive(commandID string, t *archiveTransfer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.archives[commandID] = t
+}
+
+// closeArchive -> command finished, late transfer is refused
+func (h *Hub) closeArchive(commandID string) {
+	h.mu.Lock()
+	t, exists := h.archives[commandID]
+	delete(h.archives, commandID)
+	h.mu.Unlock()
+
+	if exists {
+		t.close()
+	}
+}
+
+// claimArchive -> transfer of command sent to node, used only once
+func (h *Hub) claimArchive(nodeID, commandID string, export bool) (*archiveTransfer, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, exists := h.archives[commandID]
+	if !exists || t.nodeID != nodeID || (t.w != nil) != export {
+		return nil, ErrNoArchive
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.claimed || t.closed {
+		return nil, ErrNoArchive
+	}
+	t.claimed = true
+	return t, nil
+}
+
+// UploadArchive -> export command: archive from agent is written to caller of ExportVolume
+func (h *Hub) UploadArchive(nodeID, commandID string, body io.Reader) error {
+	t, err := h.claimArchive(nodeID, commandID, true)
+	if err != nil {
+		return err
+	}
+	_, t.err = io.Copy(t, body)
+	close(t.done)
+	return t.err
+}
+
+// DownloadArchive -> import command: archive of caller of ImportVolume is sent to agent
+func (h *Hub) DownloadArchive(nodeID, commandID string, w io.Writer) error {
+	t, err := h.claimArchive(nodeID, commandID, false)
+	if err != nil {
+		return err
+	}
+	_, t.err = io.Copy(w, t)
+	close(t.done)
+	return t.err
+}
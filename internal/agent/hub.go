@@ -0,0 +1,331 @@
+// Package agent. hub.go -> сторона control plane: регистрация агентов,
+// heartbeat и очередь команд для каждого узла (long-poll).
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/client"
+	"github.com/exitae337/gorchester/internal/types"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultHeartbeatInterval -> how often agent sends heartbeat
+	DefaultHeartbeatInterval = 10 * time.Second
+	// DefaultPollWait -> how long commands request is held open
+	DefaultPollWait = 25 * time.Second
+
+	// commands waiting for agent per node
+	queueSize = 256
+	// commands returned by one poll
+	maxBatch = 32
+)
+
+var (
+	// ErrNodeNotAgent -> node from config or API has its own runtime, agent can't take it over
+	ErrNodeNotAgent = errors.New("node is not managed by agent")
+	// ErrNodeToken -> live agent node re-registers only with token issued to it
+	ErrNodeToken = errors.New("invalid or missing node token")
+)
+
+// NodeRegistry -> scheduler methods used by hub
+type NodeRegistry interface {
+	GetNode(ctx context.Context, nodeID string) (*types.Node, error)
+	RegisterNode(ctx context.Context, node *types.Node) error
+	NodeHeartbeat(ctx context.Context, nodeID string, report *types.NodeReport) error
+}
+
+// Hub -> connected agents and their command queues
+type Hub struct {
+	nodes    NodeRegistry
+	runtimes *client.Pool
+	logger   *slog.Logger
+
+	heartbeatInterval time.Duration
+	pollWait          time.Duration
+
+	mu       sync.Mutex
+	queues   map[string]chan Command       // nodeID -> commands
+	pending  map[string]chan CommandResult // commandID -> waiting caller
+	lastPoll map[string]time.Time          // nodeID -> last commands request
+	polling  map[string]int                // nodeID -> open commands requests
+	waiters  map[string]string             // commandID -> nodeID
+	tokens   map[string]string             // nodeID -> node token issued on register
+	archives map[string]*archiveTransfer   // commandID -> volume archive streamed over PathArchive
+}
+
+// NewHub -> hub, agent nodes get their runtime in pool on register
+func NewHub(nodes NodeRegistry, runtimes *client.Pool, logger *slog.Logger) *Hub {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Hub{
+		nodes:             nodes,
+		runtimes:          runtimes,
+		logger:            logger.With("component", "agent-hub"),
+		heartbeatInterval: DefaultHeartbeatInterval,
+		pollWait:          DefaultPollWait,
+		queues:            make(map[string]chan Command),
+		pending:           make(map[string]chan CommandResult),
+		lastPoll:          make(map[string]time.Time),
+		polling:           make(map[string]int),
+		waiters:           make(map[string]string),
+		tokens:            make(map[string]string),
+		archives:          make(map[string]*archiveTransfer),
+	}
+}
+
+// Register -> add node (or attach agent to node from config) and route its containers to agent
+func (h *Hub) Register(ctx context.Context, req RegisterRequest) (*RegisterResponse, error) {
+	const op = "agent.Hub.Register"
+
+	if req.NodeID == "" {
+		return nil, fmt.Errorf("%s: node_id is required", op)
+	}
+
+	existing, err := h.nodes.GetNode(ctx, req.NodeID)
+	if err == nil {
+		if err := h.checkReregister(existing, req.NodeToken); err != nil {
+			return nil, fmt.Errorf("%s: node %s: %w", op, req.NodeID, err)
+		}
+	} else {
+		if req.CPU <= 0 || req.Memory <= 0 {
+			return nil, fmt.Errorf("%s: cpu and memory capacity are required for new node", op)
+		}
+
+		node := types.NewNode(types.NodeConfig{
+			ID:       req.NodeID,
+			Hostname: req.Hostname,
+			IP:       req.IP,
+			CPU:      req.CPU,
+			Memory:   req.Memory,
+			Disk:     req.Disk,
+			Labels:   req.Labels,
+		})
+		if err := h.nodes.RegisterNode(ctx, node); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// Runtime of agent node is kept on re-register, queued commands stay
+	if rt, ok := h.runtimes.Get(req.NodeID).(*remoteRuntime); !ok || rt.hub != h {
+		h.runtimes.SetRuntime(req.NodeID, &remoteRuntime{hub: h, nodeID: req.NodeID})
+	}
+	if err := h.nodes.NodeHeartbeat(ctx, req.NodeID, nil); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Node token replaces join token for all later requests of agent, previous token stops working
+	token, err := NewToken()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	h.mu.Lock()
+	h.tokens[req.NodeID] = token
+	h.mu.Unlock()
+
+	h.logger.Info("agent registered",
+		"node_id", req.NodeID,
+		"hostname", req.Hostname,
+		"ip", req.IP)
+
+	return &RegisterResponse{
+		NodeID:            req.NodeID,
+		NodeToken:         token,
+		HeartbeatInterval: int(h.heartbeatInterval / time.Second),
+		PollWait:          int(h.pollWait / time.Second),
+	}, nil
+}
+
+// checkReregister -> only agent node can be registered again: with its token, or without it when node is lost (agent restarted)
+func (h *Hub) checkReregister(node *types.Node, token string) error {
+	node.Mu.RLock()
+	isAgent, lost := node.Agent, node.Status == types.NodeStatusNotReady
+	node.Mu.RUnlock()
+
+	if !isAgent {
+		return ErrNodeNotAgent
+	}
+	if !lost && !h.Authenticate(node.ID, token) {
+		return ErrNodeToken
+	}
+	return nil
+}
+
+// Authenticate -> token was issued to node on register
+func (h *Hub) Authenticate(nodeID, token string) bool {
+	h.mu.Lock()
+	expected, exists := h.tokens[nodeID]
+	h.mu.Unlock()
+	return exists && token != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
+
+// Forget -> node left cluster, its agent must join again
+func (h *Hub) Forget(nodeID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.tokens, nodeID)
+	delete(h.queues, nodeID)
+	delete(h.lastPoll, nodeID)
+}
+
+// Heartbeat -> node alive with usage report
+func (h *Hub) Heartbeat(ctx context.Context, hb Heartbeat) error {
+	report := hb.Report
+	report.ReportedAt = time.Now()
+	return h.nodes.NodeHeartbeat(ctx, hb.NodeID, &report)
+}
+
+// Poll -> commands for node, waits until first command or poll timeout
+func (h *Hub) Poll(ctx context.Context, nodeID string) []Command {
+	h.mu.Lock()
+	queue := h.queueLocked(nodeID)
+	h.polling[nodeID]++
+	h.lastPoll[nodeID] = time.Now()
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		h.polling[nodeID]--
+		h.lastPoll[nodeID] = time.Now()
+		h.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(h.pollWait)
+	defer timer.Stop()
+
+	commands := make([]Command, 0)
+	select {
+	case <-ctx.Done():
+		return commands
+	case <-timer.C:
+		return commands
+	case cmd := <-queue:
+		commands = append(commands, cmd)
+	}
+
+	for len(commands) < maxBatch {
+		select {
+		case cmd := <-queue:
+			commands = append(commands, cmd)
+		default:
+			return commands
+		}
+	}
+	return commands
+}
+
+// Complete -> result from agent wakes up caller (only node that got the command may answer)
+func (h *Hub) Complete(result CommandResult) {
+	h.mu.Lock()
+	waiter, exists := h.pending[result.ID]
+	if exists && h.waiters[result.ID] != result.NodeID {
+		exists = false
+	}
+	if exists {
+		delete(h.pending, result.ID)
+		delete(h.waiters, result.ID)
+	}
+	h.mu.Unlock()
+
+	if !exists {
+		h.logger.Debug("result for unknown or expired command",
+			"command_id", result.ID,
+			"node_id", result.NodeID)
+		return
+	}
+	waiter <- result
+}
+
+// Connected -> agent is polling for commands
+func (h *Hub) Connected(nodeID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.connectedLocked(nodeID)
+}
+
+func (h *Hub) connectedLocked(nodeID string) bool {
+	if h.polling[nodeID] > 0 {
+		return true
+	}
+	last, exists := h.lastPoll[nodeID]
+	return exists && time.Since(last) < h.pollWait+h.heartbeatInterval
+}
+
+func (h *Hub) queueLocked(nodeID string) chan Command {
+	queue, exists := h.queues[nodeID]
+	if !exists {
+		queue = make(chan Command, queueSize)
+		h.queues[nodeID] = queue
+	}
+	return queue
+}
+
+// call -> send command to node agent and wait for result
+func (h *Hub) call(ctx context.Context, nodeID string, cmd Command, timeout time.Duration) (CommandResult, error) {
+	h.mu.Lock()
+	if !h.connectedLocked(nodeID) {
+		h.mu.Unlock()
+		return CommandResult{}, fmt.Errorf("agent of node %s is not connected", nodeID)
+	}
+
+	if cmd.ID == "" {
+		cmd.ID = uuid.New().String()
+	}
+	cmd.Deadline = time.Now().Add(timeout)
+	waiter := make(chan CommandResult, 1)
+	h.pending[cmd.ID] = waiter
+	h.waiters[cmd.ID] = nodeID
+
+	select {
+	case h.queueLocked(nodeID) <- cmd:
+	default:
+		delete(h.pending, cmd.ID)
+		delete(h.waiters, cmd.ID)
+		h.mu.Unlock()
+		return CommandResult{}, fmt.Errorf("command queue of node %s is full", nodeID)
+	}
+	h.mu.Unlock()
+
+	forget := func() {
+		h.mu.Lock()
+		delete(h.pending, cmd.ID)
+		delete(h.waiters, cmd.ID)
+		h.mu.Unlock()
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-waiter:
+		if result.Error != "" {
+			return result, errors.New(result.Error)
+		}
+		return result, nil
+	case <-ctx.Done():
+		forget()
+		return CommandResult{}, ctx.Err()
+	case <-timer.C:
+		forget()
+		return CommandResult{}, fmt.Errorf("agent of node %s did not answer %s in %s", nodeID, cmd.Op, timeout)
+	}
+}
+
+// NewToken -> random hex token (join and node tokens)
+func NewToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
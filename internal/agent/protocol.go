@@ -0,0 +1,97 @@
+// Package agent. Агент узла и его связь с control plane.
+// protocol.go -> сообщения между gorchester-agent и API control plane.
+package agent
+
+import (
+	"time"
+
+	"github.com/exitae337/gorchester/internal/client"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// Agent API paths on control plane
+const (
+	PathRegister  = "/api/v1/agent/register"
+	PathHeartbeat = "/api/v1/agent/heartbeat"
+	PathCommands  = "/api/v1/agent/commands"
+	PathResults   = "/api/v1/agent/results"
+	PathArchive   = "/api/v1/agent/archive" // volume archive of export (POST) or import (GET) command
+)
+
+// Operations executed by agent on its Docker daemon
+const (
+	OpCreate        = "create"
+	OpStart         = "start"
+	OpStop          = "stop"
+	OpUpdateCPUSet  = "update_cpuset"
+	OpRemove        = "remove"
+	OpStatus        = "status"
+	OpHealth        = "health"
+	OpDiskUsage     = "disk_usage"
+	OpDisconnect    = "disconnect"
+	OpPull          = "pull"
+	OpList          = "list"
+	OpRemoveVolume  = "remove_volume"
+	OpExportVolume  = "export_volume"
+	OpImportVolume  = "import_volume"
+	OpExec          = "exec"
+	OpListNetworks  = "list_networks"
+	OpRemoveNetwork = "remove_network"
+)
+
+// RegisterRequest -> node joins cluster with join token (capacity is detected by agent when not set)
+type RegisterRequest struct {
+	NodeID   string            `json:"node_id"`
+	Hostname string            `json:"hostname"`
+	IP       string            `json:"ip"`
+	CPU      int64             `json:"cpu"`    // in millicores
+	Memory   int64             `json:"memory"` // in bytes
+	Disk     int64             `json:"disk"`   // in bytes
+	Labels   map[string]string `json:"labels,omitempty"`
+
+	NodeToken string `json:"node_token,omitempty"` // Token of previous registration, required for live agent node
+}
+
+// RegisterResponse -> how often agent must report
+type RegisterResponse struct {
+	NodeID            string `json:"node_id"`
+	NodeToken         string `json:"node_token"` // Bearer token for heartbeat, commands and results
+	HeartbeatInterval int    `json:"heartbeat_interval_sec"`
+	PollWait          int    `json:"poll_wait_sec"`
+}
+
+// Heartbeat -> node is alive, real usage and running containers
+type Heartbeat struct {
+	NodeID string           `json:"node_id"`
+	Report types.NodeReport `json:"report"`
+}
+
+// Command -> container operation for agent
+type Command struct {
+	ID          string             `json:"id"`
+	Op          string             `json:"op"`
+	Task        *types.Task        `json:"task,omitempty"`
+	ContainerID string             `json:"container_id,omitempty"`
+	Image       string             `json:"image,omitempty"`
+	HealthCheck *types.HealthCheck `json:"health_check,omitempty"`
+	Filters     map[string]string  `json:"filters,omitempty"`
+	Volume      string             `json:"volume,omitempty"`
+	Network     string             `json:"network,omitempty"`
+	VolumeSpec  *types.TaskVolume  `json:"volume_spec,omitempty"` // import: volume to recreate
+	Cmd         []string           `json:"cmd,omitempty"`
+	CPUSet      string             `json:"cpu_set,omitempty"` // update_cpuset: new cores of container
+	Secrets     map[string][]byte  `json:"secrets,omitempty"` // create: secret values by name, task has no values in JSON
+	Deadline    time.Time          `json:"deadline"`
+}
+
+// CommandResult -> result of command (fields by operation)
+type CommandResult struct {
+	ID         string                   `json:"id"`
+	NodeID     string                   `json:"node_id"`
+	Value      string                   `json:"value,omitempty"`
+	Healthy    bool                     `json:"healthy,omitempty"`
+	Size       int64                    `json:"size,omitempty"`
+	Containers []client.DockerContainer `json:"containers,omitempty"`
+	Networks   []string                 `json:"networks,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+}
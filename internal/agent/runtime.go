@@ -0,0 +1,152 @@
+// Package agent. runtime.go -> ContainerManager узла с агентом:
+// каждая операция становится командой агенту, результат ждём синхронно.
+package agent
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/docker/docker/client"
+	orchclient "github.com/exitae337/gorchester/internal/client"
+	"github.com/exitae337/gorchester/internal/types"
+	"github.com/google/uuid"
+)
+
+const (
+	// create and pull may download image
+	longCommandTimeout = 10 * time.Minute
+	commandTimeout     = 1 * time.Minute
+	// export and import stream whole volume
+	archiveCommandTimeout = 2 * time.Hour
+)
+
+// remoteRuntime -> containers of node are managed by its agent
+type remoteRuntime struct {
+	hub    *Hub
+	nodeID string
+}
+
+var _ orchclient.ContainerManager = (*remoteRuntime)(nil)
+
+func (r *remoteRuntime) CreateContainer(ctx context.Context, task *types.Task, logger *slog.Logger) (string, error) {
+	cmd := Command{Op: OpCreate, Task: task}
+	for _, sec := range task.Secrets {
+		if cmd.Secrets == nil {
+			cmd.Secrets = make(map[string][]byte, len(task.Secrets))
+		}
+		cmd.Secrets[sec.Name] = sec.Value
+	}
+	result, err := r.hub.call(ctx, r.nodeID, cmd, longCommandTimeout)
+	return result.Value, err
+}
+
+func (r *remoteRuntime) StartContainer(ctx context.Context, containerID string) error {
+	_, err := r.hub.call(ctx, r.nodeID, Command{Op: OpStart, ContainerID: containerID}, commandTimeout)
+	return err
+}
+
+func (r *remoteRuntime) StopContainer(ctx context.Context, containerID string) error {
+	_, err := r.hub.call(ctx, r.nodeID, Command{Op: OpStop, ContainerID: containerID}, commandTimeout)
+	return err
+}
+
+func (r *remoteRuntime) UpdateContainerCPUSet(ctx context.Context, containerID, cpuSet string) error {
+	_, err := r.hub.call(ctx, r.nodeID, Command{Op: OpUpdateCPUSet, ContainerID: containerID, CPUSet: cpuSet}, commandTimeout)
+	return err
+}
+
+func (r *remoteRuntime) RemoveContainer(ctx context.Context, containerID string) error {
+	_, err := r.hub.call(ctx, r.nodeID, Command{Op: OpRemove, ContainerID: containerID}, commandTimeout)
+	return err
+}
+
+func (r *remoteRuntime) GetConatinerStatus(ctx context.Context, containerID string) (string, error) {
+	result, err := r.hub.call(ctx, r.nodeID, Command{Op: OpStatus, ContainerID: containerID}, commandTimeout)
+	return result.Value, err
+}
+
+func (r *remoteRuntime) ListContainers(ctx context.Context, filters map[string]string) ([]orchclient.DockerContainer, error) {
+	result, err := r.hub.call(ctx, r.nodeID, Command{Op: OpList, Filters: filters}, commandTimeout)
+	return result.Containers, err
+}
+
+func (r *remoteRuntime) PullImage(ctx context.Context, image string, logger *slog.Logger) error {
+	_, err := r.hub.call(ctx, r.nodeID, Command{Op: OpPull, Image: image}, longCommandTimeout)
+	return err
+}
+
+func (r *remoteRuntime) CheckContainerHealth(ctx context.Context, containerID string, healthOpts *types.HealthCheck) (bool, error) {
+	result, err := r.hub.call(ctx, r.nodeID, Command{
+		Op:          OpHealth,
+		ContainerID: containerID,
+		HealthCheck: healthOpts,
+	}, commandTimeout)
+	return result.Healthy, err
+}
+
+func (r *remoteRuntime) GetContainerDiskUsage(ctx context.Context, containerID string) (int64, error) {
+	result, err := r.hub.call(ctx, r.nodeID, Command{Op: OpDiskUsage, ContainerID: containerID}, commandTimeout)
+	return result.Size, err
+}
+
+// GetClient -> no direct Docker access, metrics of agent nodes come from heartbeats
+func (r *remoteRuntime) GetClient() *client.Client {
+	return nil
+}
+
+func (r *remoteRuntime) DisconnectFromNetwork(ctx context.Context, containerID string) error {
+	_, err := r.hub.call(ctx, r.nodeID, Command{Op: OpDisconnect, ContainerID: containerID}, commandTimeout)
+	return err
+}
+
+func (r *remoteRuntime) RemoveVolume(ctx context.Context, name string) error {
+	_, err := r.hub.call(ctx, r.nodeID, Command{Op: OpRemoveVolume, Volume: name}, commandTimeout)
+	return err
+}
+
+// ExportVolume -> agent uploads archive to PathArchive while command runs, then answers
+func (r *remoteRuntime) ExportVolume(ctx context.Context, name, image string, w io.Writer) error {
+	cmd := Command{ID: uuid.New().String(), Op: OpExportVolume, Volume: name, Image: image}
+	transfer := newArchiveTransfer(r.nodeID, w, nil)
+	r.hub.openArchive(cmd.ID, transfer)
+	defer r.hub.closeArchive(cmd.ID)
+
+	if _, err := r.hub.call(ctx, r.nodeID, cmd, archiveCommandTimeout); err != nil {
+		return err
+	}
+	return transfer.finished()
+}
+
+// ImportVolume -> agent downloads archive from PathArchive while command runs
+func (r *remoteRuntime) ImportVolume(ctx context.Context, volume types.TaskVolume, image string, archive io.Reader) error {
+	cmd := Command{ID: uuid.New().String(), Op: OpImportVolume, VolumeSpec: &volume, Image: image}
+	transfer := newArchiveTransfer(r.nodeID, nil, archive)
+	r.hub.openArchive(cmd.ID, transfer)
+	defer r.hub.closeArchive(cmd.ID)
+
+	_, err := r.hub.call(ctx, r.nodeID, cmd, archiveCommandTimeout)
+	return err
+}
+
+func (r *remoteRuntime) ListNetworks(ctx context.Context) ([]string, error) {
+	result, err := r.hub.call(ctx, r.nodeID, Command{Op: OpListNetworks}, commandTimeout)
+	return result.Networks, err
+}
+
+func (r *remoteRuntime) RemoveNetwork(ctx context.Context, name string) error {
+	_, err := r.hub.call(ctx, r.nodeID, Command{Op: OpRemoveNetwork, Network: name}, commandTimeout)
+	return err
+}
+
+func (r *remoteRuntime) ExecCommand(ctx context.Context, containerID string, cmd []string) (string, error) {
+	result, err := r.hub.call(ctx, r.nodeID, Command{Op: OpExec, ContainerID: containerID, Cmd: cmd}, commandTimeout)
+	return result.Value, err
+}
+
+// Close -> node removed from pool, its agent must join again
+func (r *remoteRuntime) Close() error {
+	r.hub.Forget(r.nodeID)
+	return nil
+}
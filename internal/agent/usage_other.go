@@ -0,0 +1,20 @@
+//go:build !linux
+
+// Package agent. usage_other.go -> загрузка узла доступна только на linux.
+package agent
+
+import "errors"
+
+var errUsageUnsupported = errors.New("node usage is supported only on linux")
+
+func readCPUTimes() (uint64, uint64, error) {
+	return 0, 0, errUsageUnsupported
+}
+
+func memoryInfo() (int64, int64, error) {
+	return 0, 0, errUsageUnsupported
+}
+
+func diskInfo(path string) (int64, int64, error) {
+	return 0, 0, errUsageUnsupported
+}
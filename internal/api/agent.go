@@ -0,0 +1,156 @@
+// Package api. agent.go -> эндпоинты для gorchester-agent:
+// регистрация узла (join token), heartbeat, получение команд и отправка результатов (токен узла).
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/exitae337/gorchester/internal/agent"
+)
+
+func (s *APIServer) registerAgentRoutes() {
+	s.mux.HandleFunc(agent.PathRegister, s.handleAgentRegister)
+	s.mux.HandleFunc(agent.PathHeartbeat, s.handleAgentHeartbeat)
+	s.mux.HandleFunc(agent.PathCommands, s.handleAgentCommands)
+	s.mux.HandleFunc(agent.PathResults, s.handleAgentResults)
+	s.mux.HandleFunc(agent.PathArchive, s.handleAgentArchive)
+}
+
+// Agent registration -> node joins cluster
+func (s *APIServer) handleAgentRegister(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if !s.requireJoinToken(w, r) {
+		return
+	}
+
+	var req agent.RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	resp, err := s.agents.Register(r.Context(), req)
+	switch {
+	case errors.Is(err, agent.ErrNodeToken):
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	case errors.Is(err, agent.ErrNodeNotAgent):
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// Agent heartbeat -> usage report
+func (s *APIServer) handleAgentHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var hb agent.Heartbeat
+	if err := json.NewDecoder(r.Body).Decode(&hb); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if !s.requireNodeToken(w, r, hb.NodeID) {
+		return
+	}
+
+	// Unknown node -> 404, agent registers again
+	if err := s.agents.Heartbeat(r.Context(), hb); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Agent commands -> long-poll
+func (s *APIServer) handleAgentCommands(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	nodeID := r.URL.Query().Get("node_id")
+	if !s.requireNodeToken(w, r, nodeID) {
+		return
+	}
+	if _, err := s.sched.GetNode(r.Context(), nodeID); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.agents.Poll(r.Context(), nodeID))
+}
+
+// Agent command result
+func (s *APIServer) handleAgentResults(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var result agent.CommandResult
+	if err := json.NewDecoder(r.Body).Decode(&result); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if !s.requireNodeToken(w, r, result.NodeID) {
+		return
+	}
+
+	s.agents.Complete(result)
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Agent volume archive -> POST upload of export, GET download for import (streamed)
+func (s *APIServer) handleAgentArchive(w http.ResponseWriter, r *http.Request) {
+	nodeID := r.URL.Query().Get("node_id")
+	commandID := r.URL.Query().Get("command_id")
+
+	switch r.Method {
+	case http.MethodPost:
+		if !s.requireNodeToken(w, r, nodeID) {
+			return
+		}
+		if err := s.agents.UploadArchive(nodeID, commandID, r.Body); err != nil {
+			if errors.Is(err, agent.ErrNoArchive) {
+				writeError(w, http.StatusConflict, err.Error())
+				return
+			}
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	case http.MethodGet:
+		if !s.requireNodeToken(w, r, nodeID) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-tar")
+		err := s.agents.DownloadArchive(nodeID, commandID, w)
+		switch {
+		case errors.Is(err, agent.ErrNoArchive):
+			writeError(w, http.StatusConflict, err.Error())
+		case err != nil:
+			// Body is already streaming, agent gets truncated archive and fails import
+			s.logger.Warn("failed to send volume archive to agent",
+				"node_id", nodeID,
+				"command_id", commandID,
+				"error", err)
+		}
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
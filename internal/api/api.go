@@ -5,8 +5,12 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/exitae337/gorchester/internal/agent"
+	"github.com/exitae337/gorchester/internal/config"
 	"github.com/exitae337/gorchester/internal/core"
 	"github.com/exitae337/gorchester/internal/metrics"
 	"github.com/exitae337/gorchester/internal/scheduler"
@@ -17,23 +21,32 @@ type APIServer struct {
 	orch    *core.Orchestrator
 	sched   *scheduler.SimpleScheduler
 	metrics *metrics.MetricsStore
+	agents  *agent.Hub
 	logger  *slog.Logger
 	mux     *http.ServeMux
+
+	joinToken  *apiToken
+	adminToken *apiToken
 }
 
 func NewAPIServer(
 	orch *core.Orchestrator,
 	sched *scheduler.SimpleScheduler,
 	metricsStore *metrics.MetricsStore,
+	agents *agent.Hub,
+	tokens Tokens,
 	logger *slog.Logger,
 ) *APIServer {
 	s := &APIServer{
 		orch:    orch,
 		sched:   sched,
 		metrics: metricsStore,
+		agents:  agents,
 		logger:  logger.With("component", "api"),
 		mux:     http.NewServeMux(),
 	}
+	s.joinToken = s.newAPIToken(tokens.Join, tokens.DataDir, "join.token", "join_token")
+	s.adminToken = s.newAPIToken(tokens.Admin, tokens.DataDir, "admin.token", "admin_token")
 	s.registerRoutes()
 	return s
 }
@@ -58,14 +71,38 @@ func (s *APIServer) registerRoutes() {
 	// Config strategy
 	s.mux.HandleFunc("/api/v1/config/strategy", s.handleStrategy)
 
-	// Change Node Status
+	// Change Node Status, remove Node
 	s.mux.HandleFunc("/api/v1/nodes/", s.handleNodeStatusByPath)
+
+	// Join token
+	s.mux.HandleFunc("/api/v1/join-token/rotate", s.handleJoinTokenRotate)
+
+	// Rebalance plan
+	s.mux.HandleFunc("/api/v1/rebalance", s.handleRebalance)
+
+	// Volumes
+	s.mux.HandleFunc("/api/v1/volumes", s.handleVolumes)
+	s.mux.HandleFunc("/api/v1/volumes/", s.handleVolumeByPath)
+
+	// Networks
+	s.mux.HandleFunc("/api/v1/networks", s.handleNetworks)
+
+	// Volume snapshots
+	s.mux.HandleFunc("/api/v1/snapshots", s.handleSnapshots)
+	s.mux.HandleFunc("/api/v1/snapshots/", s.handleSnapshotByPath)
+
+	// Secrets
+	s.mux.HandleFunc("/api/v1/secrets", s.handleSecrets)
+	s.mux.HandleFunc("/api/v1/secrets/", s.handleSecretByPath)
+
+	// Node agents
+	s.registerAgentRoutes()
 }
 
 // Start API Server
 func (s *APIServer) Start(addr string) error {
 	s.logger.Info("API server starting", "addr", addr)
-	return http.ListenAndServe(addr, s.mux)
+	return http.ListenAndServe(addr, s.redactResponses(s.mux))
 }
 
 // Health check
@@ -99,6 +136,7 @@ func (s *APIServer) handleServices(w http.ResponseWriter, r *http.Request) {
 				"pending":      0,
 				"failed":       0,
 				"stopped":      0,
+				"node_lost":    0,
 			}
 		}
 		svc := services[task.ServiceName]
@@ -111,11 +149,18 @@ func (s *APIServer) handleServices(w http.ResponseWriter, r *http.Request) {
 			svc["failed"] = svc["failed"].(int) + 1
 		case types.TaskStatusStopped:
 			svc["stopped"] = svc["stopped"].(int) + 1
+		case types.TaskStatusNodeLost:
+			svc["node_lost"] = svc["node_lost"].(int) + 1
 		}
 	}
 
 	result := make([]map[string]interface{}, 0, len(services))
-	for _, svc := range services {
+	for name, svc := range services {
+		// Orphaned tasks have no budget
+		if disruption, err := s.orch.DisruptionStatus(ctx, name); err == nil {
+			svc["disruptions_allowed"] = disruption.DisruptionsAllowed
+			svc["disruption_budget"] = disruption.Budget
+		}
 		result = append(result, svc)
 	}
 
@@ -155,31 +200,77 @@ func (s *APIServer) handleServiceByPath(w http.ResponseWriter, r *http.Request)
 			if len(containerID) > 12 {
 				containerID = containerID[:12]
 			}
-			serviceTasks = append(serviceTasks, map[string]interface{}{
+			entry := map[string]interface{}{
 				"task_id":       task.ID[:8],
 				"status":        task.Status,
 				"node_id":       task.NodeID,
 				"container_id":  containerID,
 				"restart_count": task.RestartCount,
-			})
+			}
+			if task.Ordinal != nil {
+				entry["ordinal"] = *task.Ordinal
+				entry["hostname"] = task.Hostname
+			}
+			serviceTasks = append(serviceTasks, entry)
 		}
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
+	response := map[string]interface{}{
 		"service_name": serviceName,
 		"tasks":        serviceTasks,
 		"total":        len(serviceTasks),
-	})
+	}
+	if disruption, err := s.orch.DisruptionStatus(ctx, serviceName); err == nil {
+		response["disruption"] = disruption
+	}
+
+	writeJSON(w, http.StatusOK, response)
 
 }
 
 // Nodes Handler
 func (s *APIServer) handleNodes(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+	switch r.Method {
+	case http.MethodGet:
+		s.listNodes(w, r)
+	case http.MethodPost:
+		s.registerNode(w, r)
+	default:
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// Register Node -> join token required
+func (s *APIServer) registerNode(w http.ResponseWriter, r *http.Request) {
+	if !s.requireJoinToken(w, r) {
+		return
+	}
+
+	var nc types.NodeConfig
+	if err := json.NewDecoder(r.Body).Decode(&nc); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if err := config.ValidateNode(nc); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	node, err := s.orch.AddNode(r.Context(), nc)
+	if err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":       node.ID,
+		"hostname": node.Hostname,
+		"status":   node.Status,
+	})
+}
+
+// List Nodes
+func (s *APIServer) listNodes(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 	nodes, err := s.sched.GetNodes(ctx)
 	if err != nil {
@@ -191,17 +282,42 @@ func (s *APIServer) handleNodes(w http.ResponseWriter, r *http.Request) {
 	for _, node := range nodes {
 		cpuPct := float64(node.UsedCPU) / float64(node.Resources.CPU) * 100
 		memPct := float64(node.UsedMemory) / float64(node.Resources.Memory) * 100
+		var diskPct float64
+		if node.Resources.Disk > 0 {
+			diskPct = float64(node.UsedDisk) / float64(node.Resources.Disk) * 100
+		}
+
+		// Extended resources: name -> capacity / used
+		extended := make(map[string]map[string]int64, len(node.Resources.Extended))
+		node.Mu.RLock()
+		for name, capacity := range node.Resources.Extended {
+			extended[name] = map[string]int64{
+				"capacity": capacity,
+				"used":     node.UsedExtended[name],
+			}
+		}
+		isAgent, report := node.Agent, node.Report
+		unschedulable := node.Unschedulable
+		node.Mu.RUnlock()
 
 		result = append(result, map[string]interface{}{
-			"id":           node.ID,
-			"hostname":     node.Hostname,
-			"status":       node.Status,
-			"cpu_cores":    node.Resources.CPU / 1000,
-			"cpu_used_pct": cpuPct,
-			"mem_total_mb": node.Resources.Memory / 1024 / 1024,
-			"mem_used_pct": memPct,
-			"task_count":   node.TaskCount,
-			"last_seen":    node.LastSeen.Format("2006-01-02T15:04:05"),
+			"id":            node.ID,
+			"hostname":      node.Hostname,
+			"status":        node.Status,
+			"unschedulable": unschedulable,
+			"cpu_cores":     node.Resources.CPU / 1000,
+			"cpu_used_pct":  cpuPct,
+			"mem_total_mb":  node.Resources.Memory / 1024 / 1024,
+			"mem_used_pct":  memPct,
+			"disk_total_mb": node.Resources.Disk / 1024 / 1024,
+			"disk_used_pct": diskPct,
+			"task_count":    node.TaskCount,
+			"pinned_cpus":   s.sched.GetAllocatedCPUs(node.ID),
+			"extended":      extended,
+			"last_seen":     node.LastSeen.Format("2006-01-02T15:04:05"),
+			"agent":         isAgent,
+			"report":        report,
+			"maintenance":   s.orch.MaintenanceStatus(node.ID),
 		})
 	}
 
@@ -228,6 +344,10 @@ func (s *APIServer) handleTasks(w http.ResponseWriter, r *http.Request) {
 		if len(containerID) > 12 {
 			containerID = containerID[:12]
 		}
+		var qos types.QoSClass
+		if task.ServiceConfig != nil {
+			qos = task.ServiceConfig.Resources.QoSClass()
+		}
 		result = append(result, map[string]interface{}{
 			"task_id":       task.ID[:8],
 			"service_name":  task.ServiceName,
@@ -236,6 +356,8 @@ func (s *APIServer) handleTasks(w http.ResponseWriter, r *http.Request) {
 			"node_id":       task.NodeID,
 			"container_id":  containerID,
 			"restart_count": task.RestartCount,
+			"ports":         task.PortMapping,
+			"qos":           qos,
 		})
 	}
 
@@ -279,40 +401,95 @@ func (s *APIServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
 
 // Change Node status Handler
 func (s *APIServer) handleNodeStatusByPath(w http.ResponseWriter, r *http.Request) {
-	// Parse path: /api/v1/nodes/{id}/drain or /api/v1/nodes/{id}/activate
+	// Parse path: /api/v1/nodes/{id}/{cordon|uncordon|drain|maintenance|activate}
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/nodes/")
 	parts := strings.Split(path, "/")
 
+	// DELETE /api/v1/nodes/{id}
+	if len(parts) == 1 && r.Method == http.MethodDelete {
+		s.removeNode(w, r, parts[0])
+		return
+	}
+
+	// PATCH /api/v1/nodes/{id}
+	if len(parts) == 1 && r.Method == http.MethodPatch {
+		s.updateNode(w, r, parts[0])
+		return
+	}
+
 	if len(parts) < 2 {
-		writeError(w, http.StatusBadRequest, "path must be /nodes/{id}/drain or /nodes/{id}/activate")
+		writeError(w, http.StatusBadRequest, "path must be /nodes/{id}/{cordon|uncordon|drain|activate}")
 		return
 	}
 
 	nodeID := parts[0]
 	action := parts[1]
 
+	// GET /api/v1/nodes/{id}/drain -> drain progress
+	if action == "drain" && r.Method == http.MethodGet {
+		status, err := s.orch.DrainStatus(nodeID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+		return
+	}
+
+	// GET /api/v1/nodes/{id}/maintenance -> windows and phase
+	if action == "maintenance" && r.Method == http.MethodGet {
+		status := s.orch.MaintenanceStatus(nodeID)
+		if status == nil {
+			writeError(w, http.StatusNotFound, "node "+nodeID+" has no maintenance windows")
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+		return
+	}
+
 	if r.Method != http.MethodPut {
 		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 
 	switch action {
-	case "drain":
-		err := s.sched.UpdateNodeStatus(ctx, nodeID, types.NodeStatusDraining)
-		if err != nil {
+	case "cordon", "uncordon":
+		if err := s.orch.CordonNode(ctx, nodeID, action == "cordon"); err != nil {
 			writeError(w, http.StatusNotFound, err.Error())
 			return
 		}
-		writeJSON(w, http.StatusOK, map[string]string{
-			"status":  "draining",
-			"node":    nodeID,
-			"message": "Node drained. Containers will be rescheduled on next reconcile.",
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"node":          nodeID,
+			"unschedulable": action == "cordon",
 		})
-	case "activate":
-		err := s.sched.UpdateNodeStatus(ctx, nodeID, types.NodeStatusReady)
+	case "drain":
+		var timeout time.Duration
+		if value := r.URL.Query().Get("timeout"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed <= 0 {
+				writeError(w, http.StatusBadRequest, "timeout must be a positive duration, e.g. 10m")
+				return
+			}
+			timeout = parsed
+		}
+
+		if _, err := s.sched.GetNode(ctx, nodeID); err != nil {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+
+		status, err := s.orch.DrainNode(ctx, nodeID, timeout)
 		if err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusAccepted, status)
+	case "maintenance":
+		s.setMaintenance(w, r, nodeID)
+	case "activate":
+		if err := s.orch.ActivateNode(ctx, nodeID); err != nil {
 			writeError(w, http.StatusNotFound, err.Error())
 			return
 		}
@@ -322,7 +499,274 @@ func (s *APIServer) handleNodeStatusByPath(w http.ResponseWriter, r *http.Reques
 			"message": "Node reactivated. Ready for scheduling.",
 		})
 	default:
-		writeError(w, http.StatusBadRequest, "action must be 'drain' or 'activate'")
+		writeError(w, http.StatusBadRequest, "action must be 'cordon', 'uncordon', 'drain', 'maintenance' or 'activate'")
+	}
+}
+
+// Set maintenance windows -> durations as strings ("2h"), empty list removes windows
+func (s *APIServer) setMaintenance(w http.ResponseWriter, r *http.Request, nodeID string) {
+	var req struct {
+		Windows []struct {
+			Schedule    string `json:"schedule"`
+			Duration    string `json:"duration"`
+			DrainBefore string `json:"drain_before"`
+		} `json:"windows"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	windows := make([]types.MaintenanceWindow, 0, len(req.Windows))
+	for _, rw := range req.Windows {
+		window := types.MaintenanceWindow{Schedule: rw.Schedule}
+		var err error
+		if window.Duration, err = time.ParseDuration(rw.Duration); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid duration "+rw.Duration)
+			return
+		}
+		if rw.DrainBefore != "" {
+			if window.DrainBefore, err = time.ParseDuration(rw.DrainBefore); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid drain_before "+rw.DrainBefore)
+				return
+			}
+		}
+		windows = append(windows, window)
+	}
+	if err := config.ValidateMaintenance(windows); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := s.orch.SetMaintenance(r.Context(), nodeID, windows); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, s.orch.MaintenanceStatus(nodeID))
+}
+
+// Update Node -> labels and capacity, "evict": true moves tasks whose required affinity broke
+func (s *APIServer) updateNode(w http.ResponseWriter, r *http.Request, nodeID string) {
+	var req struct {
+		types.NodeUpdate
+		Evict bool `json:"evict"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	if _, err := s.sched.GetNode(r.Context(), nodeID); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	result, err := s.orch.UpdateNode(r.Context(), nodeID, req.NodeUpdate, req.Evict)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Remove Node -> join token required, tasks are migrated before node is forgotten
+func (s *APIServer) removeNode(w http.ResponseWriter, r *http.Request, nodeID string) {
+	if !s.requireJoinToken(w, r) {
+		return
+	}
+
+	if err := s.orch.RemoveNode(r.Context(), nodeID); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "removing",
+		"node":    nodeID,
+		"message": "Node is unschedulable. Tasks are migrated, then node is removed.",
+	})
+}
+
+// Rebalance plan -> moves the rebalancer would make now
+func (s *APIServer) handleRebalance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	moves, err := s.orch.PlanRebalance(context.Background())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"moves": moves,
+		"count": len(moves),
+	})
+}
+
+// Volumes -> tracked volumes with node binding
+func (s *APIServer) handleVolumes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	volumes, err := s.orch.ListVolumes(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, volumes)
+}
+
+// Volume by Path -> /api/v1/volumes/{name}[/snapshot]
+func (s *APIServer) handleVolumeByPath(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/volumes/"), "/")
+	name := parts[0]
+	if name == "" || len(parts) > 2 {
+		writeError(w, http.StatusBadRequest, "path must be /volumes/{name}[/snapshot]")
+		return
+	}
+
+	volumes, err := s.orch.ListVolumes(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	known := false
+	for _, v := range volumes {
+		known = known || v.Name == name
+	}
+	if !known {
+		writeError(w, http.StatusNotFound, "volume not found")
+		return
+	}
+
+	// POST /api/v1/volumes/{name}/snapshot -> archive volume into backups
+	if len(parts) == 2 {
+		if parts[1] != "snapshot" {
+			writeError(w, http.StatusNotFound, "unknown volume action: "+parts[1])
+			return
+		}
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		s.snapshotVolume(w, r, name)
+		return
+	}
+
+	// DELETE /api/v1/volumes/{name} -> remove unused volume with its data
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if err := s.orch.DeleteVolume(r.Context(), name); err != nil {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{
+		"volume": name,
+		"status": "deleted",
+	})
+}
+
+// snapshotVolume -> ?quiesce=false skips pre_snapshot command of service
+func (s *APIServer) snapshotVolume(w http.ResponseWriter, r *http.Request, name string) {
+	quiesce := true
+	if value := r.URL.Query().Get("quiesce"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "quiesce must be true or false")
+			return
+		}
+		quiesce = parsed
+	}
+
+	snap, err := s.orch.SnapshotVolume(r.Context(), name, quiesce)
+	if err != nil {
+		// nil snapshot -> volume not ready for snapshot, otherwise archive failed
+		if snap == nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, snap)
+}
+
+// Networks -> GET /api/v1/networks: declared and own networks of services
+func (s *APIServer) handleNetworks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.orch.ListNetworks())
+}
+
+// Snapshots -> GET /api/v1/snapshots[?volume=name]
+func (s *APIServer) handleSnapshots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.orch.ListSnapshots(r.URL.Query().Get("volume")))
+}
+
+// Snapshot by Path -> /api/v1/snapshots/{id}[/restore]
+func (s *APIServer) handleSnapshotByPath(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/snapshots/"), "/")
+	id := parts[0]
+	if id == "" || len(parts) > 2 {
+		writeError(w, http.StatusBadRequest, "path must be /snapshots/{id}[/restore]")
+		return
+	}
+
+	snap, err := s.orch.GetSnapshot(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// POST /api/v1/snapshots/{id}/restore -> replace volume data with snapshot
+	if len(parts) == 2 {
+		if parts[1] != "restore" {
+			writeError(w, http.StatusNotFound, "unknown snapshot action: "+parts[1])
+			return
+		}
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if err := s.orch.RestoreSnapshot(r.Context(), id); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{
+			"snapshot": id,
+			"volume":   snap.Volume,
+			"status":   "restored",
+		})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, snap)
+	case http.MethodDelete:
+		if err := s.orch.DeleteSnapshot(id); err != nil {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{
+			"snapshot": id,
+			"status":   "deleted",
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
 	}
 }
 
@@ -0,0 +1,160 @@
+// Package api. secrets.go -> управление секретами (значения только записываются,
+// прочитать их через API нельзя) и вырезание значений секретов из ответов.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/exitae337/gorchester/internal/agent"
+	"github.com/exitae337/gorchester/internal/core"
+	"github.com/exitae337/gorchester/internal/secrets"
+)
+
+// secretRequest -> body of create and rotate
+type secretRequest struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Secrets -> GET list, POST create (admin token)
+func (s *APIServer) handleSecrets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if !s.requireAdminToken(w, r) {
+			return
+		}
+		list, err := s.orch.ListSecrets()
+		if err != nil {
+			writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, list)
+	case http.MethodPost:
+		if !s.requireAdminToken(w, r) {
+			return
+		}
+		req, ok := readSecretRequest(w, r)
+		if !ok {
+			return
+		}
+		if !secrets.ValidName(req.Name) {
+			writeError(w, http.StatusBadRequest, "name must be letters, digits, '_', '.', '-' and start with letter or digit")
+			return
+		}
+		sec, err := s.orch.CreateSecret(req.Name, []byte(req.Value))
+		if err != nil {
+			writeError(w, secretErrorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, sec)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// Secret by Path -> /api/v1/secrets/{name}: GET metadata, PUT rotate, DELETE (admin token)
+func (s *APIServer) handleSecretByPath(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/v1/secrets/")
+	if name == "" || strings.Contains(name, "/") {
+		writeError(w, http.StatusBadRequest, "path must be /secrets/{name}")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if !s.requireAdminToken(w, r) {
+			return
+		}
+		sec, err := s.orch.GetSecret(name)
+		if err != nil {
+			writeError(w, secretErrorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, sec)
+	case http.MethodPut:
+		if !s.requireAdminToken(w, r) {
+			return
+		}
+		req, ok := readSecretRequest(w, r)
+		if !ok {
+			return
+		}
+		sec, err := s.orch.RotateSecret(name, []byte(req.Value))
+		if err != nil {
+			writeError(w, secretErrorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, sec)
+	case http.MethodDelete:
+		if !s.requireAdminToken(w, r) {
+			return
+		}
+		if err := s.orch.DeleteSecret(name); err != nil {
+			writeError(w, secretErrorStatus(err), err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{
+			"secret": name,
+			"status": "deleted",
+		})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// readSecretRequest -> body with non-empty value up to secrets.MaxValueSize
+func readSecretRequest(w http.ResponseWriter, r *http.Request) (secretRequest, bool) {
+	var req secretRequest
+	r.Body = http.MaxBytesReader(w, r.Body, 2*secrets.MaxValueSize)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return req, false
+	}
+	if req.Value == "" || len(req.Value) > secrets.MaxValueSize {
+		writeError(w, http.StatusBadRequest, "value must be from 1 byte to 512 KiB")
+		return req, false
+	}
+	return req, true
+}
+
+func secretErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, secrets.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, secrets.ErrExists):
+		return http.StatusConflict
+	case errors.Is(err, core.ErrSecretInUse):
+		return http.StatusConflict
+	case errors.Is(err, core.ErrSecretsUnavailable):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// redactResponses -> secret values never leave API, agent commands carry them on purpose, volume archives are binary
+func (s *APIServer) redactResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, agent.PathCommands) || strings.HasPrefix(r.URL.Path, agent.PathArchive) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(&redactWriter{ResponseWriter: w, redact: s.orch.RedactSecrets}, r)
+	})
+}
+
+// redactWriter -> every written chunk is redacted (writeJSON writes body at once)
+type redactWriter struct {
+	http.ResponseWriter
+	redact func(string) string
+}
+
+func (w *redactWriter) Write(b []byte) (int, error) {
+	if _, err := w.ResponseWriter.Write([]byte(w.redact(string(b)))); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
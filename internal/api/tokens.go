@@ -0,0 +1,144 @@
+// Package api. tokens.go -> токен присоединения к кластеру (join token), токен
+// администратора для секретов и проверка Bearer-токенов в запросах.
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/exitae337/gorchester/internal/agent"
+)
+
+// Tokens -> API tokens from config, empty one is generated for this run into file in DataDir
+type Tokens struct {
+	Join    string // node registration and removal
+	Admin   string // secrets API
+	DataDir string
+}
+
+// apiToken -> bearer token, generated token is kept in file (0600) and never logged
+type apiToken struct {
+	mu    sync.RWMutex
+	value string
+	file  string // empty -> token from config
+}
+
+// valid -> constant time comparison with current token
+func (t *apiToken) valid(candidate string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return candidate != "" && subtle.ConstantTimeCompare([]byte(t.value), []byte(candidate)) == 1
+}
+
+// rotate -> new token, old one stops working (joined agents use node tokens)
+func (t *apiToken) rotate() (string, error) {
+	token, err := agent.NewToken()
+	if err != nil {
+		return "", err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.file != "" {
+		if err := writeTokenFile(t.file, token); err != nil {
+			return "", err
+		}
+	}
+	t.value = token
+	return token, nil
+}
+
+// writeTokenFile -> token readable only by owner, replaced atomically
+func writeTokenFile(file, token string) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	tmp := file + ".tmp"
+	os.Remove(tmp)
+	if err := os.WriteFile(tmp, []byte(token+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	return nil
+}
+
+// newAPIToken -> token from config, or generated into DataDir file
+func (s *APIServer) newAPIToken(value, dataDir, fileName, name string) *apiToken {
+	t := &apiToken{value: value}
+	if value != "" {
+		return t
+	}
+
+	t.file = filepath.Join(dataDir, fileName)
+	if _, err := t.rotate(); err != nil {
+		s.logger.Error("failed to generate "+name+", its endpoints are disabled", "error", err)
+		return t
+	}
+	s.logger.Warn(name+" is not set, generated one for this run", "token_file", t.file)
+	return t
+}
+
+// bearerToken -> token from "Authorization: Bearer <token>"
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// requireJoinToken -> 401 unless request has current join token
+func (s *APIServer) requireJoinToken(w http.ResponseWriter, r *http.Request) bool {
+	if !s.joinToken.valid(bearerToken(r)) {
+		writeError(w, http.StatusUnauthorized, "invalid or missing join token")
+		return false
+	}
+	return true
+}
+
+// requireAdminToken -> 401 unless request has admin token
+func (s *APIServer) requireAdminToken(w http.ResponseWriter, r *http.Request) bool {
+	if !s.adminToken.valid(bearerToken(r)) {
+		writeError(w, http.StatusUnauthorized, "invalid or missing admin token")
+		return false
+	}
+	return true
+}
+
+// requireNodeToken -> 401 unless request has token issued to node on register
+func (s *APIServer) requireNodeToken(w http.ResponseWriter, r *http.Request, nodeID string) bool {
+	if !s.agents.Authenticate(nodeID, bearerToken(r)) {
+		writeError(w, http.StatusUnauthorized, "invalid or missing node token")
+		return false
+	}
+	return true
+}
+
+// Rotate join token
+func (s *APIServer) handleJoinTokenRotate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !s.requireJoinToken(w, r) {
+		return
+	}
+
+	token, err := s.joinToken.rotate()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.logger.Info("join token rotated")
+	writeJSON(w, http.StatusOK, map[string]string{"join_token": token})
+}
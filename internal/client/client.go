@@ -4,6 +4,8 @@ package client
 
 import (
 	"context"
+	"io"
+	"log/slog"
 	"time"
 
 	"github.com/docker/docker/client"
@@ -13,11 +15,13 @@ import (
 // Interafce for Docker Client -> contract
 type ContainerManager interface {
 	// Create container
-	CreateContainer(ctx context.Context, service *types.ServiceConfig, taskID string) (string, error)
+	CreateContainer(ctx context.Context, task *types.Task, logger *slog.Logger) (string, error)
 	// Start container by ID
 	StartContainer(ctx context.Context, containerID string) error
 	// Stop container by ID
 	StopContainer(ctx context.Context, containerID string) error
+	// Change cores of running container
+	UpdateContainerCPUSet(ctx context.Context, containerID, cpuSet string) error
 	// Delete container by ID
 	RemoveContainer(ctx context.Context, containerID string) error
 	// Container status
@@ -25,17 +29,33 @@ type ContainerManager interface {
 	// List all containers by filter (or all)
 	ListContainers(ctx context.Context, filters map[string]string) ([]DockerContainer, error)
 	// Download image for container
-	PullImage(ctx context.Context, image string) error
+	PullImage(ctx context.Context, image string, logger *slog.Logger) error
 	// Check container health
-	CheckContainerHealth(ctx context.Context, containerID string, healthOpts *types.HealthCheck) error
-	// Image exists
-	imageExists(ctx context.Context, image string) (bool, error)
+	CheckContainerHealth(ctx context.Context, containerID string, healthOpts *types.HealthCheck) (bool, error)
+	// Size of container writable layer
+	GetContainerDiskUsage(ctx context.Context, containerID string) (int64, error)
 	// GetClient
 	GetClient() *client.Client
 	// Disconnect from network
 	DisconnectFromNetwork(ctx context.Context, containerID string) error
+	// Delete named volume
+	RemoveVolume(ctx context.Context, name string) error
+	// Tar archive of named volume through helper container
+	ExportVolume(ctx context.Context, name, image string, w io.Writer) error
+	// Recreate named volume from archive of ExportVolume
+	ImportVolume(ctx context.Context, volume types.TaskVolume, image string, r io.Reader) error
+	// Networks created by orchestrator
+	ListNetworks(ctx context.Context) ([]string, error)
+	// Delete network without containers
+	RemoveNetwork(ctx context.Context, name string) error
+	// Run command in container
+	ExecCommand(ctx context.Context, containerID string, cmd []string) (string, error)
+	// Close connection
+	Close() error
 }
 
+var _ ContainerManager = (*DockerClient)(nil)
+
 // Docker container struct
 type DockerContainer struct {
 	ID      string            `json:"id"`         // Container ID
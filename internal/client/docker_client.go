@@ -8,12 +8,17 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/containerd/errdefs"
 	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/docker/go-connections/nat"
 	"github.com/exitae337/gorchester/internal/types"
@@ -27,16 +32,32 @@ const (
 type DockerClient struct {
 	cli     *client.Client
 	timeout time.Duration
+
+	storageOnce sync.Once
+	storageOpt  atomic.Bool
+
+	// volume archives may take longer than timeout
+	opts      []client.Opt
+	streamMu  sync.Mutex
+	streamCli *client.Client
+
+	networkMu sync.Mutex
 }
 
-// New Docker Client
+// New Docker Client -> local daemon (DOCKER_HOST env)
 func NewDockerClient() (*DockerClient, error) {
+	return newDockerClient(client.FromEnv)
+}
+
+// newDockerClient -> client with options and checked connection
+func newDockerClient(opts ...client.Opt) (*DockerClient, error) {
 	const op = "client.NewDockerClient"
-	cli, err := client.NewClientWithOpts(
-		client.FromEnv,
+	baseOpts := append([]client.Opt{}, opts...)
+	opts = append(opts,
 		client.WithAPIVersionNegotiation(),
 		client.WithTimeout(defaultTimeout),
 	)
+	cli, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("%s: error with create docker client: %w", op, err)
 	}
@@ -45,19 +66,36 @@ func NewDockerClient() (*DockerClient, error) {
 	defer cancel()
 
 	if _, err := cli.Ping(ctx); err != nil {
+		cli.Close()
 		return nil, fmt.Errorf("%s: failed to ping Docker Daemon: %w", op, err)
 	}
 
 	return &DockerClient{
 		cli:     cli,
 		timeout: defaultTimeout,
+		opts:    baseOpts,
 	}, nil
 }
 
-// Create Container: Create and start container by configuration
-func (dc *DockerClient) CreateContainer(ctx context.Context, service *types.ServiceConfig, taskID string, logger *slog.Logger) (string, error) {
+// Create Container: Create and start container for the Task by service configuration
+func (dc *DockerClient) CreateContainer(ctx context.Context, task *types.Task, logger *slog.Logger) (_ string, err error) {
 	const op = "client.CreateContainer"
 
+	service := task.ServiceConfig
+	taskID := task.ID
+
+	// Host ports allocated by scheduler, service ports as fallback
+	ports := task.PortMapping
+	if len(ports) == 0 {
+		ports = service.Ports
+	}
+
+	// Cores pinned by scheduler, service cpu_set as fallback
+	cpuSet := task.CPUSet
+	if cpuSet == "" {
+		cpuSet = service.Resources.CPUSet
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, dc.timeout)
 	defer cancel()
 
@@ -76,25 +114,32 @@ func (dc *DockerClient) CreateContainer(ctx context.Context, service *types.Serv
 	logger.Debug("CreateContainer: image ready, creating container")
 
 	containerConfig := &container.Config{
+		Hostname:     task.Hostname, // stateful replica, empty -> Docker default
 		Image:        service.Image,
-		Env:          convertEnvVars(service.Env),
+		Env:          append(convertEnvVars(service.Env), secretEnv(task.Secrets)...),
 		Cmd:          service.Command,
-		ExposedPorts: createExposedPorts(service.Ports),
+		ExposedPorts: createExposedPorts(ports),
 		Labels: map[string]string{
 			"gorchester.service": service.ServiceName,
 			"gorchester.task_id": taskID,
 			"managed-by":         "gorchester",
 		},
 	}
+	if len(task.Networks) > 0 {
+		containerConfig.Labels[networksLabel] = networkNames(task.Networks)
+	}
 
 	hostConfig := &container.HostConfig{
-		PortBindings:  createPortBindings(service.Ports),
+		PortBindings:  createPortBindings(ports),
 		RestartPolicy: getRestartPolicy(service.RestartPolicy),
 		Resources: container.Resources{
-			NanoCPUs:   int64(service.Resources.CPUMilliCores * 1_000_000),
-			Memory:     service.Resources.MemoryBytes,
-			MemorySwap: service.Resources.MemoryBytes,
-			CpusetCpus: service.Resources.CPUSet,
+			// Docker enforces limits, request is a soft reservation
+			NanoCPUs:          service.Resources.CPULimit() * 1_000_000,
+			Memory:            service.Resources.MemoryLimit(),
+			MemorySwap:        service.Resources.MemoryLimit(),
+			MemoryReservation: service.Resources.MemoryRequest(),
+			CPUShares:         cpuShares(service.Resources.CPURequest()),
+			CpusetCpus:        cpuSet,
 		},
 		Binds:       service.Volumes,
 		NetworkMode: container.NetworkMode(service.NetworkMode),
@@ -102,23 +147,95 @@ func (dc *DockerClient) CreateContainer(ctx context.Context, service *types.Serv
 		ExtraHosts:  service.ExtraHosts,
 	}
 
+	// Named volumes -> created on this node on first use
+	if len(task.Volumes) > 0 {
+		mounts, err := dc.ensureVolumes(ctx, task.Volumes)
+		if err != nil {
+			logger.Error("CreateContainer: failed to prepare volumes", "error", err)
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		hostConfig.Mounts = mounts
+	}
+
+	// Secret files -> tmpfs of node, removed with container
+	secretMounts, err := dc.writeSecretFiles(taskID, task.Secrets)
+	if err != nil {
+		logger.Error("CreateContainer: failed to prepare secrets", "error", err)
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	if secretMounts != nil {
+		hostConfig.Mounts = append(hostConfig.Mounts, secretMounts...)
+		defer func() {
+			if err != nil {
+				removeSecretFiles(taskID)
+			}
+		}()
+	}
+
+	// Managed networks -> created on this node on first use, first one is container network mode
+	var netConfig *network.NetworkingConfig
+	if len(task.Networks) > 0 && service.NetworkMode == "" {
+		if err := dc.ensureNetworks(ctx, task.Networks); err != nil {
+			logger.Error("CreateContainer: failed to prepare networks", "error", err)
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		hostConfig.NetworkMode = container.NetworkMode(task.Networks[0].Name)
+		netConfig = networkingConfig(task.Networks)
+	}
+
+	// Ephemeral storage limit, only if storage driver supports it
+	if service.Resources.DiskBytes > 0 && dc.storageOptSupported(ctx) {
+		hostConfig.StorageOpt = map[string]string{
+			"size": fmt.Sprintf("%d", service.Resources.DiskBytes),
+		}
+	}
+
+	// Stateful replica keeps its name across restarts and moves
 	containerName := generateContainerName(service.ServiceName, taskID)
+	if task.Hostname != "" {
+		containerName = task.Hostname
+	}
 	logger.Debug("CreateContainer: creating container", "name", containerName)
 
 	resp, err := dc.cli.ContainerCreate(
 		ctx,
 		containerConfig,
 		hostConfig,
-		nil,
+		netConfig,
 		nil,
 		containerName,
 	)
 
+	if err != nil && hostConfig.StorageOpt != nil && isStorageOptError(err) {
+		logger.Warn("CreateContainer: storage driver rejected size limit, creating without it", "error", err)
+		dc.disableStorageOpt()
+		hostConfig.StorageOpt = nil
+		resp, err = dc.cli.ContainerCreate(ctx, containerConfig, hostConfig, netConfig, nil, containerName)
+	}
+
+	if err != nil && task.Hostname != "" && cerrdefs.IsConflict(err) {
+		logger.Warn("CreateContainer: name is held by previous container of replica, removing it", "name", containerName)
+		if rmErr := dc.removeStaleReplica(ctx, containerName, service.ServiceName); rmErr != nil {
+			return "", fmt.Errorf("%s: container name %s is taken: %w", op, containerName, rmErr)
+		}
+		resp, err = dc.cli.ContainerCreate(ctx, containerConfig, hostConfig, netConfig, nil, containerName)
+	}
+
 	if err != nil {
 		logger.Error("CreateContainer: failed to create container", "error", err)
 		return "", fmt.Errorf("%s: error creating container: %w", op, err)
 	}
 
+	if len(task.Networks) > 1 && service.NetworkMode == "" {
+		if err := dc.connectNetworks(ctx, resp.ID, task.Networks[1:]); err != nil {
+			logger.Error("CreateContainer: failed to attach networks, cleaning up", "error", err)
+			cleanUpCtx, cleanUpCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cleanUpCancel()
+			dc.cli.ContainerRemove(cleanUpCtx, resp.ID, container.RemoveOptions{})
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
 	logger.Debug("CreateContainer: container created, starting", "container_id", resp.ID[:12])
 
 	if err := dc.StartContainer(ctx, resp.ID); err != nil {
@@ -133,6 +250,18 @@ func (dc *DockerClient) CreateContainer(ctx context.Context, service *types.Serv
 	return resp.ID, nil
 }
 
+// removeStaleReplica -> remove container of the same stateful replica left behind (e.g. by lost node)
+func (dc *DockerClient) removeStaleReplica(ctx context.Context, name, serviceName string) error {
+	inspect, err := dc.cli.ContainerInspect(ctx, name)
+	if err != nil {
+		return err
+	}
+	if inspect.Config == nil || inspect.Config.Labels["gorchester.service"] != serviceName {
+		return fmt.Errorf("container %s is not a replica of %s", name, serviceName)
+	}
+	return dc.cli.ContainerRemove(ctx, inspect.ID, container.RemoveOptions{Force: true})
+}
+
 // Disconnect container from network before Deleting
 func (dc *DockerClient) DisconnectFromNetwork(ctx context.Context, containerID string) error {
 	const op = "client.DisconnectFromNetwork"
@@ -146,12 +275,16 @@ func (dc *DockerClient) DisconnectFromNetwork(ctx context.Context, containerID s
 		return nil
 	}
 
-	// Disconnect
-	for networkName := range inspect.NetworkSettings.Networks {
-		if networkName != "bridge" && networkName != "host" && networkName != "none" {
-			if err := dc.cli.NetworkDisconnect(ctx, networkName, containerID, true); err != nil {
-				continue
-			}
+	// Disconnect from networks orchestrator attached, so they can be removed
+	if inspect.Config == nil || inspect.NetworkSettings == nil || inspect.Config.Labels[networksLabel] == "" {
+		return nil
+	}
+	for _, networkName := range strings.Split(inspect.Config.Labels[networksLabel], ",") {
+		if _, attached := inspect.NetworkSettings.Networks[networkName]; !attached {
+			continue
+		}
+		if err := dc.cli.NetworkDisconnect(ctx, networkName, containerID, true); err != nil {
+			continue
 		}
 	}
 
@@ -189,17 +322,46 @@ func (dc *DockerClient) StopContainer(ctx context.Context, containerID string) e
 	return nil
 }
 
+// Update container cpuset -> shared cores changed while container runs
+func (dc *DockerClient) UpdateContainerCPUSet(ctx context.Context, containerID, cpuSet string) error {
+	const op = "client.UpdateContainerCPUSet"
+
+	ctx, cancel := context.WithTimeout(ctx, dc.timeout)
+	defer cancel()
+
+	if _, err := dc.cli.ContainerUpdate(ctx, containerID, container.UpdateConfig{
+		Resources: container.Resources{CpusetCpus: cpuSet},
+	}); err != nil {
+		return fmt.Errorf("%s: failed to update cpuset of container: %s -> error: %w", op, containerID[:12], err)
+	}
+
+	return nil
+}
+
 // Remove container
 func (dc *DockerClient) RemoveContainer(ctx context.Context, containerID string) error {
 	const op = "client.RemoveDocker"
 	ctx, cancel := context.WithTimeout(ctx, dc.timeout)
 	defer cancel()
 
+	// Secret files of task live on this host only with local daemon
+	taskID := ""
+	if dc.isLocal() {
+		if inspect, err := dc.cli.ContainerInspect(ctx, containerID); err == nil && inspect.Config != nil {
+			taskID = inspect.Config.Labels["gorchester.task_id"]
+		}
+		defer removeSecretFiles(taskID)
+	}
+
 	if err := dc.cli.ContainerRemove(ctx, containerID, container.RemoveOptions{
 		Force:         true,
 		RemoveVolumes: true,
 		RemoveLinks:   true,
 	}); err != nil {
+		// Already gone (node rebooted, removed by hand) -> nothing to do
+		if cerrdefs.IsNotFound(err) {
+			return nil
+		}
 		return fmt.Errorf("%s: error with container %s removing: %w", op, containerID[:12], err)
 	}
 
@@ -253,6 +415,13 @@ func (dc *DockerClient) PullImage(ctx context.Context, im string, logger *slog.L
 func (dc *DockerClient) Close() error {
 	const op = "client.Close"
 
+	dc.streamMu.Lock()
+	if dc.streamCli != nil {
+		dc.streamCli.Close()
+		dc.streamCli = nil
+	}
+	dc.streamMu.Unlock()
+
 	if dc.cli != nil {
 		if err := dc.cli.Close(); err != nil {
 			return fmt.Errorf("%s: failed to close docker client: %w", op, err)
@@ -324,6 +493,42 @@ func (dc *DockerClient) CheckContainerHealth(ctx context.Context, containerID st
 	}
 }
 
+// List containers by labels (all containers when filters are empty)
+func (dc *DockerClient) ListContainers(ctx context.Context, labels map[string]string) ([]DockerContainer, error) {
+	const op = "client.ListContainers"
+
+	ctx, cancel := context.WithTimeout(ctx, dc.timeout)
+	defer cancel()
+
+	args := filters.NewArgs()
+	for key, value := range labels {
+		args.Add("label", key+"="+value)
+	}
+
+	list, err := dc.cli.ContainerList(ctx, container.ListOptions{All: true, Filters: args})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list containers: %w", op, err)
+	}
+
+	result := make([]DockerContainer, 0, len(list))
+	for _, c := range list {
+		name := ""
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+		result = append(result, DockerContainer{
+			ID:      c.ID,
+			Name:    name,
+			Image:   c.Image,
+			Status:  c.Status,
+			State:   c.State,
+			Created: time.Unix(c.Created, 0),
+			Labels:  c.Labels,
+		})
+	}
+	return result, nil
+}
+
 // Get Docker Client for collecting Metrics
 func (dc *DockerClient) GetClient() *client.Client {
 	return dc.cli
@@ -465,8 +670,39 @@ func (dc *DockerClient) execInContainer(ctx context.Context, containerID string,
 	return inspectResp.ExitCode, string(output), nil
 }
 
+// ExecCommand -> run command in container, non-zero exit code is an error
+func (dc *DockerClient) ExecCommand(ctx context.Context, containerID string, cmd []string) (string, error) {
+	const op = "client.ExecCommand"
+
+	exitCode, output, err := dc.execInContainer(ctx, containerID, &types.ExecConfig{
+		Cmd:          cmd,
+		AttachStdOut: true,
+		AttachStdErr: true,
+	})
+	if err != nil {
+		return output, fmt.Errorf("%s: %w", op, err)
+	}
+	if exitCode != 0 {
+		return output, fmt.Errorf("%s: command %v exited with code %d, output: %s",
+			op, cmd, exitCode, strings.TrimSpace(output))
+	}
+	return output, nil
+}
+
 // ========= HELPERS =========
 
+// cpuShares -> relative weight from CPU request (1000m = 1024 shares)
+func cpuShares(milliCores int64) int64 {
+	if milliCores <= 0 {
+		return 0
+	}
+	shares := milliCores * 1024 / 1000
+	if shares < 2 {
+		shares = 2
+	}
+	return shares
+}
+
 func convertEnvVars(envVars []string) []string {
 	result := make([]string, 0, len(envVars))
 	result = append(result, envVars...)
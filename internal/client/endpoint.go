@@ -0,0 +1,194 @@
+// Package client. endpoint.go -> подключение к Docker daemon удалённого узла:
+// unix-сокет, tcp (с TLS) или ssh через "docker system dial-stdio".
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+const (
+	// stderr of ssh kept for error messages
+	maxSSHStderr = 4096
+)
+
+// NewDockerClientForEndpoint -> client for node Docker daemon
+func NewDockerClientForEndpoint(ep types.DockerEndpoint) (*DockerClient, error) {
+	const op = "client.NewDockerClientForEndpoint"
+
+	u, err := url.Parse(ep.Host)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid docker host %q: %w", op, ep.Host, err)
+	}
+
+	switch u.Scheme {
+	case "unix", "tcp":
+		opts := []client.Opt{client.WithHost(ep.Host)}
+		if ep.TLSCert != "" || ep.TLSCACert != "" {
+			opts = append(opts, client.WithTLSClientConfig(ep.TLSCACert, ep.TLSCert, ep.TLSKey))
+		}
+		return newDockerClient(opts...)
+	case "ssh":
+		if u.Hostname() == "" {
+			return nil, fmt.Errorf("%s: ssh host is empty in %q", op, ep.Host)
+		}
+		dialer := sshDialer(u, ep.SSHKey)
+		// Host is only used for URLs, connection goes through dialer
+		return newDockerClient(
+			client.WithHost("http://docker.example.com"),
+			client.WithDialContext(dialer),
+		)
+	default:
+		return nil, fmt.Errorf("%s: unsupported docker host scheme %q", op, u.Scheme)
+	}
+}
+
+// sshDialer -> every connection is "ssh host docker system dial-stdio"
+func sshDialer(u *url.URL, keyPath string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	args := []string{"-o", "BatchMode=yes"}
+	if keyPath != "" {
+		args = append(args, "-i", keyPath)
+	}
+	if port := u.Port(); port != "" {
+		args = append(args, "-p", port)
+	}
+	if u.User != nil && u.User.Username() != "" {
+		args = append(args, "-l", u.User.Username())
+	}
+	args = append(args, "--", u.Hostname(), "docker", "system", "dial-stdio")
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		// Not bound to ctx: connection may outlive the request (keep-alive)
+		cmd := exec.Command("ssh", args...)
+		return newCommandConn(cmd)
+	}
+}
+
+// commandConn -> net.Conn over stdin/stdout of process
+type commandConn struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.ReadCloser
+	stderr *limitedBuffer
+
+	// anything received -> EOF is a normal close
+	received  atomic.Bool
+	closeOnce sync.Once
+}
+
+// newCommandConn -> start process and wrap its pipes
+func newCommandConn(cmd *exec.Cmd) (*commandConn, error) {
+	const op = "client.newCommandConn"
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	stderr := &limitedBuffer{max: maxSSHStderr}
+	cmd.Stderr = stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("%s: failed to start %s: %w", op, cmd.Path, err)
+	}
+
+	return &commandConn{
+		cmd:    cmd,
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+	}, nil
+}
+
+// Read -> process stdout, stderr is attached to EOF before any data (connect failure)
+func (c *commandConn) Read(p []byte) (int, error) {
+	n, err := c.stdout.Read(p)
+	if n > 0 {
+		c.received.Store(true)
+	}
+	if errors.Is(err, io.EOF) && !c.received.Load() {
+		if msg := strings.TrimSpace(c.stderr.String()); msg != "" {
+			return n, fmt.Errorf("connection closed by remote: %s: %w", msg, io.EOF)
+		}
+	}
+	return n, err
+}
+
+// Write -> process stdin
+func (c *commandConn) Write(p []byte) (int, error) {
+	return c.stdin.Write(p)
+}
+
+// CloseWrite -> half-close, remote side sees EOF
+func (c *commandConn) CloseWrite() error {
+	return c.stdin.Close()
+}
+
+// Close -> kill process and wait for it once
+func (c *commandConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stdin.Close()
+		if c.cmd.Process != nil {
+			c.cmd.Process.Kill()
+		}
+		c.cmd.Wait()
+	})
+	return nil
+}
+
+func (c *commandConn) LocalAddr() net.Addr  { return dummyAddr{} }
+func (c *commandConn) RemoteAddr() net.Addr { return dummyAddr{} }
+
+// Deadlines are not supported by pipes
+func (c *commandConn) SetDeadline(t time.Time) error      { return nil }
+func (c *commandConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *commandConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// dummyAddr -> address of command connection
+type dummyAddr struct{}
+
+func (dummyAddr) Network() string { return "command" }
+func (dummyAddr) String() string  { return "command" }
+
+// limitedBuffer -> keeps first max bytes written
+type limitedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if free := b.max - b.buf.Len(); free > 0 {
+		if len(p) > free {
+			b.buf.Write(p[:free])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
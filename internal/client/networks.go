@@ -0,0 +1,125 @@
+// Package client. networks.go -> управляемые сети Docker: создание сети задачи
+// на узле перед запуском контейнера, подключение с алиасами, список и удаление сетей.
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// networksLabel -> networks container was attached to by orchestrator
+const networksLabel = "gorchester.networks"
+
+// ensureNetworks -> create missing networks of task on this daemon
+func (dc *DockerClient) ensureNetworks(ctx context.Context, networks []types.TaskNetwork) error {
+	// Daemon may accept two networks with one name -> one create at a time
+	dc.networkMu.Lock()
+	defer dc.networkMu.Unlock()
+
+	for _, n := range networks {
+		_, err := dc.cli.NetworkInspect(ctx, n.Name, network.InspectOptions{})
+		if err == nil {
+			continue
+		}
+		if !cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("failed to inspect network %s: %w", n.Name, err)
+		}
+
+		labels := make(map[string]string, len(n.Labels)+1)
+		for k, v := range n.Labels {
+			labels[k] = v
+		}
+		labels["managed-by"] = "gorchester"
+
+		options := network.CreateOptions{
+			Driver:   n.Driver,
+			Internal: n.Internal,
+			Labels:   labels,
+		}
+		if n.Subnet != "" {
+			options.IPAM = &network.IPAM{Config: []network.IPAMConfig{{Subnet: n.Subnet}}}
+		}
+		if _, err := dc.cli.NetworkCreate(ctx, n.Name, options); err != nil {
+			return fmt.Errorf("failed to create network %s: %w", n.Name, err)
+		}
+	}
+	return nil
+}
+
+// networkingConfig -> first network joins on create, others are connected before start
+func networkingConfig(networks []types.TaskNetwork) *network.NetworkingConfig {
+	if len(networks) == 0 {
+		return nil
+	}
+	first := networks[0]
+	return &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			first.Name: {Aliases: first.Aliases},
+		},
+	}
+}
+
+// connectNetworks -> attach created container to rest of task networks
+func (dc *DockerClient) connectNetworks(ctx context.Context, containerID string, networks []types.TaskNetwork) error {
+	for _, n := range networks {
+		if err := dc.cli.NetworkConnect(ctx, n.Name, containerID, &network.EndpointSettings{Aliases: n.Aliases}); err != nil {
+			return fmt.Errorf("failed to connect to network %s: %w", n.Name, err)
+		}
+	}
+	return nil
+}
+
+// networkNames -> value of networks label
+func networkNames(networks []types.TaskNetwork) string {
+	names := make([]string, 0, len(networks))
+	for _, n := range networks {
+		names = append(names, n.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// ListNetworks -> names of networks created by orchestrator on this daemon
+func (dc *DockerClient) ListNetworks(ctx context.Context) ([]string, error) {
+	const op = "client.ListNetworks"
+
+	ctx, cancel := context.WithTimeout(ctx, dc.timeout)
+	defer cancel()
+
+	networks, err := dc.cli.NetworkList(ctx, network.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "managed-by=gorchester")),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list networks: %w", op, err)
+	}
+
+	names := make([]string, 0, len(networks))
+	for _, n := range networks {
+		names = append(names, n.Name)
+	}
+	return names, nil
+}
+
+// RemoveNetwork -> delete network without containers, missing network is not an error
+func (dc *DockerClient) RemoveNetwork(ctx context.Context, name string) error {
+	const op = "client.RemoveNetwork"
+
+	ctx, cancel := context.WithTimeout(ctx, dc.timeout)
+	defer cancel()
+
+	dc.networkMu.Lock()
+	defer dc.networkMu.Unlock()
+
+	if err := dc.cli.NetworkRemove(ctx, name); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("%s: failed to remove network %s: %w", op, name, err)
+	}
+	return nil
+}
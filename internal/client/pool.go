@@ -0,0 +1,238 @@
+// Package client. pool.go -> ContainerManager для каждого узла кластера.
+// Узлы без docker endpoint используют локальный Docker daemon,
+// к недоступным удалённым узлам подключение повторяется с паузой.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+const (
+	// pause between reconnect attempts to unreachable node
+	reconnectBackoff = 30 * time.Second
+)
+
+// Pool -> container runtimes by node ID
+type Pool struct {
+	local     ContainerManager
+	endpoints map[string]types.DockerEndpoint
+	clients   map[string]ContainerManager
+	lastTry   map[string]time.Time
+	mu        sync.Mutex
+	logger    *slog.Logger
+}
+
+// NewPool -> pool with local runtime (may be nil when all nodes are remote)
+func NewPool(local ContainerManager, logger *slog.Logger) *Pool {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Pool{
+		local:     local,
+		endpoints: make(map[string]types.DockerEndpoint),
+		clients:   make(map[string]ContainerManager),
+		lastTry:   make(map[string]time.Time),
+		logger:    logger,
+	}
+}
+
+// AddNode -> connect to node Docker endpoint (endpoint is kept for reconnect on failure)
+func (p *Pool) AddNode(nodeID string, ep types.DockerEndpoint) error {
+	const op = "client.Pool.AddNode"
+
+	if !ep.IsRemote() {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.endpoints[nodeID] = ep
+	p.lastTry[nodeID] = time.Now()
+
+	cli, err := NewDockerClientForEndpoint(ep)
+	if err != nil {
+		return fmt.Errorf("%s: node %s: %w", op, nodeID, err)
+	}
+	p.clients[nodeID] = cli
+	return nil
+}
+
+// SetRuntime -> node runtime managed outside of pool (node agent)
+func (p *Pool) SetRuntime(nodeID string, runtime ContainerManager) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if old, exists := p.clients[nodeID]; exists && old != runtime {
+		old.Close()
+	}
+	delete(p.endpoints, nodeID)
+	delete(p.lastTry, nodeID)
+	p.clients[nodeID] = runtime
+}
+
+// RemoveNode -> close node connection
+func (p *Pool) RemoveNode(nodeID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cli, exists := p.clients[nodeID]; exists {
+		cli.Close()
+	}
+	delete(p.clients, nodeID)
+	delete(p.endpoints, nodeID)
+	delete(p.lastTry, nodeID)
+}
+
+// Get -> runtime of node, unavailableManager when node daemon is unreachable
+func (p *Pool) Get(nodeID string) ContainerManager {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cli, exists := p.clients[nodeID]; exists {
+		return cli
+	}
+
+	ep, remote := p.endpoints[nodeID]
+	if !remote {
+		if p.local == nil {
+			return unavailableManager{nodeID: nodeID, reason: "no local docker daemon"}
+		}
+		return p.local
+	}
+
+	if time.Since(p.lastTry[nodeID]) < reconnectBackoff {
+		return unavailableManager{nodeID: nodeID, reason: "docker endpoint unreachable"}
+	}
+
+	p.lastTry[nodeID] = time.Now()
+	cli, err := NewDockerClientForEndpoint(ep)
+	if err != nil {
+		p.logger.Warn("failed to reconnect to node docker endpoint",
+			"node_id", nodeID,
+			"host", ep.Host,
+			"error", err)
+		return unavailableManager{nodeID: nodeID, reason: err.Error()}
+	}
+
+	p.logger.Info("reconnected to node docker endpoint",
+		"node_id", nodeID,
+		"host", ep.Host)
+	p.clients[nodeID] = cli
+	return cli
+}
+
+// Close -> close all connections
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for nodeID, cli := range p.clients {
+		if err := cli.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(p.clients, nodeID)
+	}
+	if p.local != nil {
+		if err := p.local.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// unavailableManager -> every call fails, node daemon is not connected
+type unavailableManager struct {
+	nodeID string
+	reason string
+}
+
+func (u unavailableManager) err() error {
+	return fmt.Errorf("node %s runtime unavailable: %s", u.nodeID, u.reason)
+}
+
+func (u unavailableManager) CreateContainer(ctx context.Context, task *types.Task, logger *slog.Logger) (string, error) {
+	return "", u.err()
+}
+
+func (u unavailableManager) StartContainer(ctx context.Context, containerID string) error {
+	return u.err()
+}
+
+func (u unavailableManager) StopContainer(ctx context.Context, containerID string) error {
+	return u.err()
+}
+
+func (u unavailableManager) UpdateContainerCPUSet(ctx context.Context, containerID, cpuSet string) error {
+	return u.err()
+}
+
+func (u unavailableManager) RemoveContainer(ctx context.Context, containerID string) error {
+	return u.err()
+}
+
+func (u unavailableManager) GetConatinerStatus(ctx context.Context, containerID string) (string, error) {
+	return "", u.err()
+}
+
+func (u unavailableManager) ListContainers(ctx context.Context, filters map[string]string) ([]DockerContainer, error) {
+	return nil, u.err()
+}
+
+func (u unavailableManager) PullImage(ctx context.Context, image string, logger *slog.Logger) error {
+	return u.err()
+}
+
+func (u unavailableManager) CheckContainerHealth(ctx context.Context, containerID string, healthOpts *types.HealthCheck) (bool, error) {
+	return false, u.err()
+}
+
+func (u unavailableManager) GetContainerDiskUsage(ctx context.Context, containerID string) (int64, error) {
+	return 0, u.err()
+}
+
+func (u unavailableManager) GetClient() *client.Client {
+	return nil
+}
+
+func (u unavailableManager) DisconnectFromNetwork(ctx context.Context, containerID string) error {
+	return u.err()
+}
+
+func (u unavailableManager) RemoveVolume(ctx context.Context, name string) error {
+	return u.err()
+}
+
+func (u unavailableManager) ExportVolume(ctx context.Context, name, image string, w io.Writer) error {
+	return u.err()
+}
+
+func (u unavailableManager) ImportVolume(ctx context.Context, volume types.TaskVolume, image string, r io.Reader) error {
+	return u.err()
+}
+
+func (u unavailableManager) ListNetworks(ctx context.Context) ([]string, error) {
+	return nil, u.err()
+}
+
+func (u unavailableManager) RemoveNetwork(ctx context.Context, name string) error {
+	return u.err()
+}
+
+func (u unavailableManager) ExecCommand(ctx context.Context, containerID string, cmd []string) (string, error) {
+	return "", u.err()
+}
+
+func (u unavailableManager) Close() error {
+	return nil
+}
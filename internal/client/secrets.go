@@ -0,0 +1,92 @@
+// Package client. secrets.go -> доставка секретов задачи в контейнер: переменные
+// окружения и файлы на tmpfs узла (/dev/shm), смонтированные только для чтения.
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+const (
+	// shmDir -> tmpfs of node, secret files never touch disk
+	shmDir = "/dev/shm"
+	// secretsRoot -> secret files of tasks, one directory per task
+	secretsRoot = shmDir + "/gorchester-secrets"
+)
+
+// isLocal -> daemon runs on this host, its bind mounts see our files
+func (dc *DockerClient) isLocal() bool {
+	host := dc.cli.DaemonHost()
+	return strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://")
+}
+
+// secretEnv -> secrets delivered as env vars
+func secretEnv(secrets []types.TaskSecret) []string {
+	var env []string
+	for _, sec := range secrets {
+		if sec.Env != "" {
+			env = append(env, sec.Env+"="+string(sec.Value))
+		}
+	}
+	return env
+}
+
+// writeSecretFiles -> files of task on tmpfs, bind mounted read-only to their targets
+func (dc *DockerClient) writeSecretFiles(taskID string, secrets []types.TaskSecret) ([]mount.Mount, error) {
+	var files []types.TaskSecret
+	for _, sec := range secrets {
+		if sec.Env == "" {
+			files = append(files, sec)
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+
+	// Remote daemon can't mount files of this host -> node agent writes them on the node
+	if !dc.isLocal() {
+		return nil, fmt.Errorf("secret files need local docker daemon or node agent, daemon is %s", dc.cli.DaemonHost())
+	}
+	if info, err := os.Stat(shmDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("secret files need tmpfs at %s", shmDir)
+	}
+
+	if err := os.MkdirAll(secretsRoot, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", secretsRoot, err)
+	}
+	dir := filepath.Join(secretsRoot, taskID)
+	os.RemoveAll(dir)
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create secrets directory of task: %w", err)
+	}
+
+	mounts := make([]mount.Mount, 0, len(files))
+	for _, sec := range files {
+		file := filepath.Join(dir, sec.Name)
+		// Container user is unknown -> readable by all, directory above is closed on host
+		if err := os.WriteFile(file, sec.Value, 0o444); err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("failed to write secret %s: %w", sec.Name, err)
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   file,
+			Target:   sec.Target,
+			ReadOnly: true,
+		})
+	}
+	return mounts, nil
+}
+
+// removeSecretFiles -> files of task are gone with its container
+func removeSecretFiles(taskID string) {
+	if taskID == "" || strings.ContainsAny(taskID, `/\`) {
+		return
+	}
+	os.RemoveAll(filepath.Join(secretsRoot, taskID))
+}
@@ -0,0 +1,61 @@
+// Package client. storage.go -> ограничение размера writable-слоя контейнера
+// (StorageOpt size) и получение его текущего размера.
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// storageOptSupported -> storage driver can limit container rootfs size (checked once)
+func (dc *DockerClient) storageOptSupported(ctx context.Context) bool {
+	dc.storageOnce.Do(func() {
+		info, err := dc.cli.Info(ctx)
+		if err != nil {
+			return
+		}
+
+		switch info.Driver {
+		case "devicemapper", "btrfs", "zfs", "windowsfilter":
+			dc.storageOpt.Store(true)
+		case "overlay2":
+			// overlay2 supports size only on xfs with pquota mount option
+			for _, status := range info.DriverStatus {
+				if status[0] == "Backing Filesystem" && status[1] == "xfs" {
+					dc.storageOpt.Store(true)
+				}
+			}
+		}
+	})
+	return dc.storageOpt.Load()
+}
+
+// disableStorageOpt -> daemon rejected size option, do not try again
+func (dc *DockerClient) disableStorageOpt() {
+	dc.storageOnce.Do(func() {})
+	dc.storageOpt.Store(false)
+}
+
+// isStorageOptError -> container create failed because of StorageOpt
+func isStorageOptError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "storage-opt") || strings.Contains(msg, "storage opt")
+}
+
+// GetContainerDiskUsage -> size of container writable layer in bytes
+func (dc *DockerClient) GetContainerDiskUsage(ctx context.Context, containerID string) (int64, error) {
+	const op = "client.GetContainerDiskUsage"
+
+	ctx, cancel := context.WithTimeout(ctx, dc.timeout)
+	defer cancel()
+
+	inspect, _, err := dc.cli.ContainerInspectWithRaw(ctx, containerID, true)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to inspect container: %w", op, err)
+	}
+	if inspect.SizeRw == nil {
+		return 0, nil
+	}
+	return *inspect.SizeRw, nil
+}
@@ -0,0 +1,190 @@
+// Package client. volumes.go -> именованные тома Docker: создание тома задачи
+// на узле перед запуском контейнера, монтирование и удаление тома,
+// tar-архив тома и восстановление из него через вспомогательный контейнер.
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// helperMountPath -> volume mount point in helper container, root directory of archive
+const helperMountPath = "/volume"
+
+// ensureVolumes -> create missing volumes of task on this daemon, mounts for container
+func (dc *DockerClient) ensureVolumes(ctx context.Context, volumes []types.TaskVolume) ([]mount.Mount, error) {
+	mounts := make([]mount.Mount, 0, len(volumes))
+	for _, v := range volumes {
+		_, err := dc.cli.VolumeInspect(ctx, v.Name)
+		switch {
+		case cerrdefs.IsNotFound(err):
+			labels := make(map[string]string, len(v.Labels)+1)
+			for k, val := range v.Labels {
+				labels[k] = val
+			}
+			labels["managed-by"] = "gorchester"
+
+			if _, err := dc.cli.VolumeCreate(ctx, volume.CreateOptions{
+				Name:       v.Name,
+				Driver:     v.Driver,
+				DriverOpts: v.DriverOpts,
+				Labels:     labels,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to create volume %s: %w", v.Name, err)
+			}
+		case err != nil:
+			return nil, fmt.Errorf("failed to inspect volume %s: %w", v.Name, err)
+		}
+
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeVolume,
+			Source:   v.Name,
+			Target:   v.Target,
+			ReadOnly: v.ReadOnly,
+		})
+	}
+	return mounts, nil
+}
+
+// RemoveVolume -> delete volume with its data, missing volume is not an error
+func (dc *DockerClient) RemoveVolume(ctx context.Context, name string) error {
+	const op = "client.RemoveVolume"
+
+	ctx, cancel := context.WithTimeout(ctx, dc.timeout)
+	defer cancel()
+
+	if err := dc.cli.VolumeRemove(ctx, name, false); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("%s: failed to remove volume %s: %w", op, name, err)
+	}
+	return nil
+}
+
+// ExportVolume -> tar archive of volume data written to w (root directory "volume")
+func (dc *DockerClient) ExportVolume(ctx context.Context, name, image string, w io.Writer) error {
+	const op = "client.ExportVolume"
+
+	if _, err := dc.cli.VolumeInspect(ctx, name); err != nil {
+		return fmt.Errorf("%s: failed to inspect volume %s: %w", op, name, err)
+	}
+
+	helperID, err := dc.createVolumeHelper(ctx, image, mount.Mount{
+		Type:     mount.TypeVolume,
+		Source:   name,
+		Target:   helperMountPath,
+		ReadOnly: true,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer dc.removeVolumeHelper(helperID)
+
+	cli, err := dc.streamClient()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	archive, _, err := cli.CopyFromContainer(ctx, helperID, helperMountPath)
+	if err != nil {
+		return fmt.Errorf("%s: failed to read volume %s: %w", op, name, err)
+	}
+	defer archive.Close()
+
+	if _, err := io.Copy(w, archive); err != nil {
+		return fmt.Errorf("%s: failed to write archive of volume %s: %w", op, name, err)
+	}
+	return nil
+}
+
+// ImportVolume -> volume recreated empty and filled from archive of ExportVolume
+func (dc *DockerClient) ImportVolume(ctx context.Context, v types.TaskVolume, image string, r io.Reader) error {
+	const op = "client.ImportVolume"
+
+	// Volume mounted by container can't be removed -> restore never overwrites live data
+	if err := dc.cli.VolumeRemove(ctx, v.Name, false); err != nil && !cerrdefs.IsNotFound(err) {
+		return fmt.Errorf("%s: failed to remove volume %s: %w", op, v.Name, err)
+	}
+
+	v.Target = helperMountPath
+	v.ReadOnly = false
+	mounts, err := dc.ensureVolumes(ctx, []types.TaskVolume{v})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	helperID, err := dc.createVolumeHelper(ctx, image, mounts[0])
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer dc.removeVolumeHelper(helperID)
+
+	cli, err := dc.streamClient()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := cli.CopyToContainer(ctx, helperID, "/", r, container.CopyToContainerOptions{}); err != nil {
+		return fmt.Errorf("%s: failed to restore volume %s: %w", op, v.Name, err)
+	}
+	return nil
+}
+
+// createVolumeHelper -> container holding volume mount for archive API, never started
+func (dc *DockerClient) createVolumeHelper(ctx context.Context, image string, m mount.Mount) (string, error) {
+	if exists, err := dc.imageExists(ctx, image); err != nil {
+		return "", err
+	} else if !exists {
+		if err := dc.PullImage(ctx, image, slog.Default()); err != nil {
+			return "", fmt.Errorf("failed to pull helper image %s: %w", image, err)
+		}
+	}
+
+	resp, err := dc.cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:      image,
+			Entrypoint: []string{"true"}, // create needs a command, helper is not run
+			Labels: map[string]string{
+				"managed-by":      "gorchester",
+				"gorchester.role": "volume-helper",
+			},
+		},
+		&container.HostConfig{Mounts: []mount.Mount{m}},
+		nil, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to create helper container for volume %s: %w", m.Source, err)
+	}
+	return resp.ID, nil
+}
+
+// removeVolumeHelper -> helper is removed even when request context is done
+func (dc *DockerClient) removeVolumeHelper(helperID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), dc.timeout)
+	defer cancel()
+	dc.cli.ContainerRemove(ctx, helperID, container.RemoveOptions{Force: true})
+}
+
+// streamClient -> client of same daemon without request timeout, for archives of any size
+func (dc *DockerClient) streamClient() (*client.Client, error) {
+	dc.streamMu.Lock()
+	defer dc.streamMu.Unlock()
+
+	if dc.streamCli != nil {
+		return dc.streamCli, nil
+	}
+	opts := append([]client.Opt{}, dc.opts...)
+	cli, err := client.NewClientWithOpts(append(opts, client.WithAPIVersionNegotiation())...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create docker client for archives: %w", err)
+	}
+	dc.streamCli = cli
+	return cli, nil
+}
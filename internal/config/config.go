@@ -4,10 +4,17 @@ package config
 import (
 	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/exitae337/gorchester/internal/cron"
 	"github.com/exitae337/gorchester/internal/types"
 	"github.com/ilyakaznacheev/cleanenv"
 )
@@ -51,6 +58,18 @@ func MustLoad() *types.OchestratorConfig {
 func validateConfig(config *types.OchestratorConfig) error {
 	var errorString strings.Builder
 
+	// Service names for cross-service references
+	serviceNames := make(map[string]bool, len(config.Services))
+	for _, service := range config.Services {
+		serviceNames[service.ServiceName] = true
+	}
+
+	// Named volumes for service mounts
+	volumeNames := validateVolumes(config.Volumes, &errorString)
+
+	// Networks services attach to
+	networkNames := validateNetworks(config.Networks, &errorString)
+
 	for i, service := range config.Services {
 		prefix := fmt.Sprintf("service[%d]", i)
 
@@ -62,6 +81,31 @@ func validateConfig(config *types.OchestratorConfig) error {
 			errorString.WriteString(fmt.Sprintf("%s image name can't be empty: required\n", prefix))
 		}
 
+		// Ports check
+		hostPorts := make(map[string]bool)
+		for j, port := range service.Ports {
+			if port.ContainerPort <= 0 || port.ContainerPort > 65535 {
+				errorString.WriteString(fmt.Sprintf(
+					"%s ports[%d] container_port must be between 1 and 65535\n", prefix, j))
+			}
+			if port.HostPort < 0 || port.HostPort > 65535 {
+				errorString.WriteString(fmt.Sprintf(
+					"%s ports[%d] host_port must be between 0 and 65535\n", prefix, j))
+			}
+			if port.Protocol != types.TCP && port.Protocol != types.UDP {
+				errorString.WriteString(fmt.Sprintf(
+					"%s ports[%d] protocol must be one of: tcp, udp\n", prefix, j))
+			}
+			if port.HostPort != 0 {
+				key := fmt.Sprintf("%s/%d", port.Protocol, port.HostPort)
+				if hostPorts[key] {
+					errorString.WriteString(fmt.Sprintf(
+						"%s ports[%d] host_port %s is used twice\n", prefix, j, key))
+				}
+				hostPorts[key] = true
+			}
+		}
+
 		// Replicas count check
 		if service.Replicas < 0 {
 			errorString.WriteString(fmt.Sprintf("%s amount of replicas can't be negative\n", prefix))
@@ -79,14 +123,61 @@ func validateConfig(config *types.OchestratorConfig) error {
 			}
 		}
 
-		// Resources check
-		if service.Resources.CPUMilliCores < MinMilliCores {
+		// Resources check: flat values are request and limit at once
+		resources := service.Resources
+		if resources.Requests == nil && resources.Limits == nil {
+			if resources.CPUMilliCores < MinMilliCores {
+				errorString.WriteString(fmt.Sprintf(
+					"%s amount of resources (millicores) can't be less than %d\n", prefix, MinMilliCores))
+			}
+			if resources.MemoryBytes < MinMemoryBytes {
+				errorString.WriteString(fmt.Sprintf(
+					"%s amount of resources (memory in bytes) can't be less than %d bytes\n", prefix, MinMemoryBytes))
+			}
+		} else {
+			if resources.CPURequest() < 0 || resources.MemoryRequest() < 0 ||
+				resources.CPULimit() < 0 || resources.MemoryLimit() < 0 {
+				errorString.WriteString(fmt.Sprintf("%s requests and limits can't be negative\n", prefix))
+			}
+			if limit := resources.MemoryLimit(); limit > 0 && limit < MinMemoryBytes {
+				errorString.WriteString(fmt.Sprintf(
+					"%s memory limit can't be less than %d bytes\n", prefix, MinMemoryBytes))
+			}
+			if limit := resources.CPULimit(); limit > 0 && limit < resources.CPURequest() {
+				errorString.WriteString(fmt.Sprintf("%s cpu request can't be greater than limit\n", prefix))
+			}
+			if limit := resources.MemoryLimit(); limit > 0 && limit < resources.MemoryRequest() {
+				errorString.WriteString(fmt.Sprintf("%s memory request can't be greater than limit\n", prefix))
+			}
+		}
+
+		if service.Resources.DiskBytes < 0 {
 			errorString.WriteString(fmt.Sprintf(
-				"%s amount of resources (millicores) can't be less than %d\n", prefix, MinMilliCores))
+				"%s amount of resources (disk in bytes) can't be negative\n", prefix))
 		}
-		if service.Resources.MemoryBytes < MinMemoryBytes {
+
+		// Extended resources check
+		for name, amount := range service.Resources.Extended {
+			if name == "" {
+				errorString.WriteString(fmt.Sprintf("%s extended resource name can't be empty\n", prefix))
+			}
+			if amount < 0 {
+				errorString.WriteString(fmt.Sprintf(
+					"%s extended resource %s can't be negative\n", prefix, name))
+			}
+		}
+
+		// CPU pinning check
+		if service.Resources.ExclusiveCPUs < 0 {
 			errorString.WriteString(fmt.Sprintf(
-				"%s amount of resources (memory in bytes) can't be less than %d bytes\n", prefix, MinMemoryBytes))
+				"%s exclusive_cpus can't be negative\n", prefix))
+		}
+		if service.Resources.ExclusiveCPUs > 0 && service.Resources.CPUSet != "" {
+			errorString.WriteString(fmt.Sprintf(
+				"%s exclusive_cpus and cpu_set can't be used together\n", prefix))
+		}
+		if _, err := types.ParseCPUSet(service.Resources.CPUSet); err != nil {
+			errorString.WriteString(fmt.Sprintf("%s cpu_set: %v\n", prefix, err))
 		}
 
 		// Scaling policy check
@@ -99,6 +190,25 @@ func validateConfig(config *types.OchestratorConfig) error {
 				"%s scale policy max_replicas cannot be less than min_replicas\n", prefix))
 		}
 
+		// Disruption budget check
+		budget := service.DisruptionBudget
+		if budget.MinAvailable.IsSet() && budget.MaxUnavailable.IsSet() {
+			errorString.WriteString(fmt.Sprintf(
+				"%s disruption_budget can't set both min_available and max_unavailable\n", prefix))
+		}
+		if budget.MinAvailable.IsSet() {
+			if _, err := budget.MinAvailable.Resolve(service.Replicas); err != nil {
+				errorString.WriteString(fmt.Sprintf(
+					"%s disruption_budget min_available: %v\n", prefix, err))
+			}
+		}
+		if budget.MaxUnavailable.IsSet() {
+			if _, err := budget.MaxUnavailable.Resolve(service.Replicas); err != nil {
+				errorString.WriteString(fmt.Sprintf(
+					"%s disruption_budget max_unavailable: %v\n", prefix, err))
+			}
+		}
+
 		// Predictive scaling validation
 		if service.ScalePolicy.PredictiveScaling != nil && service.ScalePolicy.PredictiveScaling.Enabled {
 			ps := service.ScalePolicy.PredictiveScaling
@@ -123,27 +233,63 @@ func validateConfig(config *types.OchestratorConfig) error {
 		// Scheduling constraints validation
 		if service.SchedulingConstraints != nil {
 			for j, rule := range service.SchedulingConstraints.Affinity {
-				if rule.Type == "" {
+				if err := rule.Validate(); err != nil {
 					errorString.WriteString(fmt.Sprintf(
-						"%s scheduling_constraints.affinity[%d] type is required\n", prefix, j))
+						"%s scheduling_constraints.affinity[%d] %s\n", prefix, j, err))
 				}
-				if rule.Operator == "" {
+			}
+			for j, rule := range service.SchedulingConstraints.AntiAffinity {
+				if err := rule.Validate(); err != nil {
 					errorString.WriteString(fmt.Sprintf(
-						"%s scheduling_constraints.affinity[%d] operator is required\n", prefix, j))
+						"%s scheduling_constraints.anti_affinity[%d] %s\n", prefix, j, err))
 				}
 			}
-			for j, rule := range service.SchedulingConstraints.AntiAffinity {
-				if rule.Type == "" {
+			for j, rule := range service.SchedulingConstraints.ServiceAffinity {
+				if err := rule.Validate(); err != nil {
 					errorString.WriteString(fmt.Sprintf(
-						"%s scheduling_constraints.anti_affinity[%d] type is required\n", prefix, j))
+						"%s scheduling_constraints.service_affinity[%d] %s\n", prefix, j, err))
+				} else if !serviceNames[rule.Service] {
+					errorString.WriteString(fmt.Sprintf(
+						"%s scheduling_constraints.service_affinity[%d] unknown service %q\n", prefix, j, rule.Service))
 				}
-				if rule.Operator == "" {
+			}
+			for j, rule := range service.SchedulingConstraints.ServiceAntiAffinity {
+				if err := rule.Validate(); err != nil {
 					errorString.WriteString(fmt.Sprintf(
-						"%s scheduling_constraints.anti_affinity[%d] operator is required\n", prefix, j))
+						"%s scheduling_constraints.service_anti_affinity[%d] %s\n", prefix, j, err))
+				} else if !serviceNames[rule.Service] {
+					errorString.WriteString(fmt.Sprintf(
+						"%s scheduling_constraints.service_anti_affinity[%d] unknown service %q\n", prefix, j, rule.Service))
 				}
 			}
 		}
 
+		// Topology spread validation
+		for j, spread := range service.TopologySpread {
+			if spread.TopologyKey == "" {
+				errorString.WriteString(fmt.Sprintf(
+					"%s topology_spread[%d] topology_key is required\n", prefix, j))
+			}
+			if spread.MaxSkew < 1 {
+				errorString.WriteString(fmt.Sprintf(
+					"%s topology_spread[%d] max_skew must be at least 1\n", prefix, j))
+			}
+			if spread.WhenUnsatisfiable != types.DoNotSchedule && spread.WhenUnsatisfiable != types.ScheduleAnyway {
+				errorString.WriteString(fmt.Sprintf(
+					"%s topology_spread[%d] when_unsatisfiable must be one of: DoNotSchedule, ScheduleAnyway\n", prefix, j))
+			}
+		}
+
+		// Volumes validation
+		validateServiceVolumes(prefix, service, volumeNames, &errorString)
+		validateBackup(prefix, service, &errorString)
+
+		// Networks validation
+		validateServiceNetworks(prefix, service, networkNames, &errorString)
+
+		// Secrets validation
+		validateServiceSecrets(prefix, service, &errorString)
+
 		// Health check validation
 		if service.HealthCheck != nil && service.HealthCheck.Type != "" {
 			if service.HealthCheck.Interval < time.Second {
@@ -185,12 +331,466 @@ func validateConfig(config *types.OchestratorConfig) error {
 		}
 	}
 
+	// Nodes NUMA layout and extended resources check
+	for i, node := range config.Nodes {
+		validateNode(fmt.Sprintf("node[%d]", i), node, &errorString)
+	}
+
+	// Dynamic host ports range
+	if config.HostPortRange.Start < 1024 || config.HostPortRange.End > 65535 ||
+		config.HostPortRange.Start > config.HostPortRange.End {
+		errorString.WriteString(fmt.Sprintf(
+			"host_port_range must be within 1024-65535 and start <= end, got %d-%d\n",
+			config.HostPortRange.Start, config.HostPortRange.End))
+	}
+
+	// Rebalancer
+	if config.Rebalancer.Interval < 10*time.Second {
+		errorString.WriteString("rebalancer interval can't be less than 10 seconds\n")
+	}
+	if config.Rebalancer.MaxMovesPerCycle < 1 {
+		errorString.WriteString("rebalancer max_moves_per_cycle must be at least 1\n")
+	}
+	if config.Rebalancer.MaxNodeSkew < 1 {
+		errorString.WriteString("rebalancer max_node_skew must be at least 1\n")
+	}
+
+	// Overcommit and memory pressure
+	if config.Overcommit.CPU <= 0 || config.Overcommit.Memory <= 0 {
+		errorString.WriteString("overcommit ratios must be greater than 0\n")
+	}
+	if config.MemoryPressureThreshold <= 0 || config.MemoryPressureThreshold > 100 {
+		errorString.WriteString("memory_pressure_threshold must be between 1 and 100\n")
+	}
+
+	if config.NodeLostGracePeriod < 0 {
+		errorString.WriteString("node_lost_grace_period can't be negative\n")
+	}
+	if config.DrainTimeout < 0 {
+		errorString.WriteString("drain_timeout can't be negative\n")
+	}
+
+	// Service discovery DNS
+	validateDNS(config.DNS, config.ClusterName, &errorString)
+
+	// Ingress proxy
+	validateIngress(config.Ingress, config.Services, &errorString)
+
 	if errorString.String() == "" {
 		return nil
 	}
 	return fmt.Errorf("%s", errorString.String())
 }
 
+// ValidateNode -> node registered at runtime (API)
+func ValidateNode(node types.NodeConfig) error {
+	var errorString strings.Builder
+	validateNode("node "+node.ID, node, &errorString)
+	if errorString.String() == "" {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.TrimSpace(errorString.String()))
+}
+
+// validateNode -> capacity, NUMA layout, extended resources and docker endpoint of node
+func validateNode(prefix string, node types.NodeConfig, errorString *strings.Builder) {
+	if node.ID == "" {
+		errorString.WriteString(fmt.Sprintf("%s id can't be empty: required\n", prefix))
+	}
+	if node.CPU <= 0 || node.Memory <= 0 {
+		errorString.WriteString(fmt.Sprintf("%s cpu and memory must be positive\n", prefix))
+	}
+
+	if node.Overcommit.CPU < 0 || node.Overcommit.Memory < 0 {
+		errorString.WriteString(fmt.Sprintf("%s overcommit ratios can't be negative\n", prefix))
+	}
+
+	for name, capacity := range node.Extended {
+		if name == "" {
+			errorString.WriteString(fmt.Sprintf("%s extended resource name can't be empty\n", prefix))
+		}
+		if capacity < 0 {
+			errorString.WriteString(fmt.Sprintf(
+				"%s extended resource %s capacity can't be negative\n", prefix, name))
+		}
+	}
+
+	if node.Docker.IsRemote() {
+		validateDockerEndpoint(prefix, node.Docker, errorString)
+	}
+
+	validateMaintenance(prefix, node.Maintenance, errorString)
+
+	numaIDs := make(map[int]bool, len(node.NUMA))
+	cores := make(map[int]bool)
+	for j, numa := range node.NUMA {
+		if numaIDs[numa.ID] {
+			errorString.WriteString(fmt.Sprintf(
+				"%s numa_nodes[%d] id %d is used twice\n", prefix, j, numa.ID))
+		}
+		numaIDs[numa.ID] = true
+
+		parsed, err := types.ParseCPUSet(numa.Cores)
+		if err != nil {
+			errorString.WriteString(fmt.Sprintf("%s numa_nodes[%d] cores: %v\n", prefix, j, err))
+			continue
+		}
+		if len(parsed) == 0 {
+			errorString.WriteString(fmt.Sprintf("%s numa_nodes[%d] cores can't be empty\n", prefix, j))
+		}
+		for _, core := range parsed {
+			if cores[core] {
+				errorString.WriteString(fmt.Sprintf(
+					"%s numa_nodes[%d] core %d belongs to several NUMA nodes\n", prefix, j, core))
+			}
+			cores[core] = true
+		}
+	}
+}
+
+// validateMaintenance -> cron schedule and positive duration of every window
+func validateMaintenance(prefix string, windows []types.MaintenanceWindow, errorString *strings.Builder) {
+	for j, window := range windows {
+		if _, err := cron.Parse(window.Schedule); err != nil {
+			errorString.WriteString(fmt.Sprintf("%s maintenance[%d] schedule: %v\n", prefix, j, err))
+		}
+		if window.Duration <= 0 {
+			errorString.WriteString(fmt.Sprintf("%s maintenance[%d] duration must be positive\n", prefix, j))
+		}
+		if window.DrainBefore < 0 {
+			errorString.WriteString(fmt.Sprintf("%s maintenance[%d] drain_before can't be negative\n", prefix, j))
+		}
+	}
+}
+
+// volumeNamePattern -> names Docker accepts for volumes
+var volumeNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// validateVolumes -> unique valid names of top level volumes, returns declared names
+func validateVolumes(volumes []types.VolumeConfig, errorString *strings.Builder) map[string]bool {
+	names := make(map[string]bool, len(volumes))
+	for i, volume := range volumes {
+		prefix := fmt.Sprintf("volumes[%d]", i)
+		if !volumeNamePattern.MatchString(volume.Name) {
+			errorString.WriteString(fmt.Sprintf(
+				"%s name %q is invalid: letters, digits, '_', '.', '-' and must start with letter or digit\n", prefix, volume.Name))
+		}
+		if names[volume.Name] {
+			errorString.WriteString(fmt.Sprintf("%s name %q is declared twice\n", prefix, volume.Name))
+		}
+		names[volume.Name] = true
+	}
+	return names
+}
+
+// validateServiceVolumes -> mounts of declared volumes, claims only for stateful services
+func validateServiceVolumes(prefix string, service types.ServiceConfig, volumeNames map[string]bool, errorString *strings.Builder) {
+	targets := make(map[string]bool)
+	checkTarget := func(field string, target string) {
+		if !strings.HasPrefix(target, "/") {
+			errorString.WriteString(fmt.Sprintf("%s %s target must be an absolute path\n", prefix, field))
+		}
+		if targets[target] {
+			errorString.WriteString(fmt.Sprintf("%s %s target %q is mounted twice\n", prefix, field, target))
+		}
+		targets[target] = true
+	}
+
+	for j, mount := range service.Mounts {
+		field := fmt.Sprintf("mounts[%d]", j)
+		if !volumeNames[mount.Volume] {
+			errorString.WriteString(fmt.Sprintf("%s %s unknown volume %q\n", prefix, field, mount.Volume))
+		}
+		checkTarget(field, mount.Target)
+	}
+
+	if len(service.VolumeClaims) > 0 && service.ServiceType != types.ServiceTypeStateful {
+		errorString.WriteString(fmt.Sprintf("%s volume_claims require service_type stateful\n", prefix))
+	}
+	claims := make(map[string]bool)
+	for j, claim := range service.VolumeClaims {
+		field := fmt.Sprintf("volume_claims[%d]", j)
+		if !volumeNamePattern.MatchString(claim.Name) {
+			errorString.WriteString(fmt.Sprintf("%s %s name %q is invalid\n", prefix, field, claim.Name))
+		}
+		if claims[claim.Name] {
+			errorString.WriteString(fmt.Sprintf("%s %s name %q is used twice\n", prefix, field, claim.Name))
+		}
+		claims[claim.Name] = true
+		checkTarget(field, claim.Target)
+	}
+
+	switch service.VolumeReclaimPolicy {
+	case types.VolumeReclaimRetain, types.VolumeReclaimDelete:
+	default:
+		errorString.WriteString(fmt.Sprintf(
+			"%s volume_reclaim_policy must be one of: retain, delete\n", prefix))
+	}
+}
+
+// validateNetworks -> unique valid names and subnets of top level networks, returns declared names
+func validateNetworks(networks []types.NetworkConfig, errorString *strings.Builder) map[string]bool {
+	names := make(map[string]bool, len(networks))
+	for i, network := range networks {
+		prefix := fmt.Sprintf("networks[%d]", i)
+		if !volumeNamePattern.MatchString(network.Name) {
+			errorString.WriteString(fmt.Sprintf(
+				"%s name %q is invalid: letters, digits, '_', '.', '-' and must start with letter or digit\n", prefix, network.Name))
+		}
+		if types.IsImplicitNetwork(network.Name) {
+			errorString.WriteString(fmt.Sprintf(
+				"%s name %q: prefix %q is reserved for own networks of services\n", prefix, network.Name, types.ImplicitNetworkPrefix))
+		}
+		if names[network.Name] {
+			errorString.WriteString(fmt.Sprintf("%s name %q is declared twice\n", prefix, network.Name))
+		}
+		names[network.Name] = true
+
+		if network.Subnet != "" {
+			if _, _, err := net.ParseCIDR(network.Subnet); err != nil {
+				errorString.WriteString(fmt.Sprintf("%s subnet %q is not a CIDR\n", prefix, network.Subnet))
+			}
+		}
+	}
+	return names
+}
+
+// validateServiceNetworks -> declared networks only, network_mode replaces networks
+func validateServiceNetworks(prefix string, service types.ServiceConfig, networkNames map[string]bool, errorString *strings.Builder) {
+	seen := make(map[string]bool, len(service.Networks))
+	for j, name := range service.Networks {
+		if !networkNames[name] {
+			errorString.WriteString(fmt.Sprintf("%s networks[%d] unknown network %q\n", prefix, j, name))
+		}
+		if seen[name] {
+			errorString.WriteString(fmt.Sprintf("%s networks[%d] network %q is listed twice\n", prefix, j, name))
+		}
+		seen[name] = true
+	}
+	if service.NetworkMode != "" && len(service.Networks) > 0 {
+		errorString.WriteString(fmt.Sprintf("%s network_mode can't be set together with networks\n", prefix))
+	}
+}
+
+// dnsLabelPattern -> one label of domain name
+var dnsLabelPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+
+// validateDNS -> listen address, zone labels, injected server reachable on port 53
+func validateDNS(dc types.DNSConfig, clusterName string, errorString *strings.Builder) {
+	if !dc.Enabled {
+		return
+	}
+	if !dnsLabelPattern.MatchString(clusterName) {
+		errorString.WriteString(fmt.Sprintf("dns requires cluster_name %q to be a valid DNS label\n", clusterName))
+	}
+	_, port, err := net.SplitHostPort(dc.ListenAddr)
+	if err != nil {
+		errorString.WriteString(fmt.Sprintf("dns listen_addr %q: %v\n", dc.ListenAddr, err))
+	}
+	for _, label := range strings.Split(dc.Domain, ".") {
+		if !dnsLabelPattern.MatchString(label) {
+			errorString.WriteString(fmt.Sprintf("dns domain %q is not a valid domain name\n", dc.Domain))
+			break
+		}
+	}
+	if dc.TTL < 0 {
+		errorString.WriteString("dns ttl can't be negative\n")
+	}
+	for i, addr := range dc.Upstream {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			errorString.WriteString(fmt.Sprintf("dns upstream[%d] %q: %v\n", i, addr, err))
+		}
+	}
+	if dc.Inject {
+		if net.ParseIP(dc.AdvertiseIP) == nil {
+			errorString.WriteString("dns inject requires advertise_ip with IP address\n")
+		}
+		// Docker passes only IP to container resolver -> port is always 53
+		if err == nil && port != "53" {
+			errorString.WriteString("dns inject requires listen_addr on port 53\n")
+		}
+	}
+}
+
+// validateIngress -> unique listeners, targets are published tcp ports of known services
+func validateIngress(ic types.IngressConfig, services []types.ServiceConfig, errorString *strings.Builder) {
+	if !ic.Enabled {
+		return
+	}
+	if ic.DrainTimeout < 0 {
+		errorString.WriteString("ingress drain_timeout can't be negative\n")
+	}
+	if ic.RefreshInterval < 0 {
+		errorString.WriteString("ingress refresh_interval can't be negative\n")
+	}
+	if len(ic.Listeners) == 0 {
+		errorString.WriteString("ingress requires at least one listener\n")
+	}
+
+	// validateTarget -> service publishes container port over tcp
+	validateTarget := func(prefix, service string, port int) {
+		if service == "" || port <= 0 {
+			errorString.WriteString(fmt.Sprintf("%s requires service and port\n", prefix))
+			return
+		}
+		for _, svc := range services {
+			if svc.ServiceName != service {
+				continue
+			}
+			for _, p := range svc.Ports {
+				if p.ContainerPort == port && p.Protocol == types.TCP {
+					return
+				}
+			}
+			errorString.WriteString(fmt.Sprintf("%s service %s doesn't publish tcp container port %d\n", prefix, service, port))
+			return
+		}
+		errorString.WriteString(fmt.Sprintf("%s unknown service %s\n", prefix, service))
+	}
+
+	names := make(map[string]bool)
+	addrs := make(map[string]bool)
+	for i, l := range ic.Listeners {
+		prefix := fmt.Sprintf("ingress listeners[%d]", i)
+		if l.Name == "" {
+			errorString.WriteString(fmt.Sprintf("%s name is required\n", prefix))
+		} else if names[l.Name] {
+			errorString.WriteString(fmt.Sprintf("%s duplicate name %s\n", prefix, l.Name))
+		}
+		names[l.Name] = true
+
+		if _, _, err := net.SplitHostPort(l.ListenAddr); err != nil {
+			errorString.WriteString(fmt.Sprintf("%s listen_addr %q: %v\n", prefix, l.ListenAddr, err))
+		} else if addrs[l.ListenAddr] {
+			errorString.WriteString(fmt.Sprintf("%s duplicate listen_addr %s\n", prefix, l.ListenAddr))
+		}
+		addrs[l.ListenAddr] = true
+
+		if l.Balance != types.BalanceRoundRobin && l.Balance != types.BalanceLeastConn {
+			errorString.WriteString(fmt.Sprintf("%s balance must be one of: round_robin, least_conn\n", prefix))
+		}
+
+		switch l.Protocol {
+		case types.IngressTCP:
+			if len(l.Routes) > 0 {
+				errorString.WriteString(fmt.Sprintf("%s routes require protocol http\n", prefix))
+			}
+			validateTarget(prefix, l.Service, l.Port)
+		case types.IngressHTTP:
+			if l.Service != "" || l.Port != 0 {
+				errorString.WriteString(fmt.Sprintf("%s http listener routes by routes, not service and port\n", prefix))
+			}
+			if len(l.Routes) == 0 {
+				errorString.WriteString(fmt.Sprintf("%s http listener requires routes\n", prefix))
+			}
+			seen := make(map[string]bool)
+			for j, r := range l.Routes {
+				routePrefix := fmt.Sprintf("%s routes[%d]", prefix, j)
+				if !strings.HasPrefix(r.Path, "/") {
+					errorString.WriteString(fmt.Sprintf("%s path must start with /\n", routePrefix))
+				}
+				key := strings.ToLower(r.Host) + r.Path
+				if seen[key] {
+					errorString.WriteString(fmt.Sprintf("%s duplicate host and path\n", routePrefix))
+				}
+				seen[key] = true
+				validateTarget(routePrefix, r.Service, r.Port)
+			}
+		default:
+			errorString.WriteString(fmt.Sprintf("%s protocol must be one of: tcp, http\n", prefix))
+		}
+	}
+}
+
+// envNamePattern -> names of env vars secrets are delivered in
+var envNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// validateServiceSecrets -> valid names, one env var or absolute file per secret, no duplicates
+func validateServiceSecrets(prefix string, service types.ServiceConfig, errorString *strings.Builder) {
+	targets := make(map[string]bool)
+	for i, ref := range service.Secrets {
+		secretPrefix := fmt.Sprintf("%s secrets[%d]", prefix, i)
+		if !volumeNamePattern.MatchString(ref.Name) {
+			errorString.WriteString(fmt.Sprintf(
+				"%s name %q is invalid: letters, digits, '_', '.', '-' and must start with letter or digit\n", secretPrefix, ref.Name))
+		}
+		if ref.Env != "" && ref.Target != "" {
+			errorString.WriteString(fmt.Sprintf("%s env and target can't be set together\n", secretPrefix))
+		}
+		key := ref.Target
+		if ref.Env != "" {
+			key = "env:" + ref.Env
+			if !envNamePattern.MatchString(ref.Env) {
+				errorString.WriteString(fmt.Sprintf("%s env %q is not a valid variable name\n", secretPrefix, ref.Env))
+			}
+		} else if !path.IsAbs(ref.Target) || path.Clean(ref.Target) == "/" {
+			errorString.WriteString(fmt.Sprintf("%s target %q must be an absolute file path\n", secretPrefix, ref.Target))
+		}
+		if targets[key] {
+			errorString.WriteString(fmt.Sprintf("%s %s is used by another secret\n", secretPrefix, strings.TrimPrefix(key, "env:")))
+		}
+		targets[key] = true
+	}
+}
+
+// validateBackup -> backup policy needs volumes, valid schedule and retention
+func validateBackup(prefix string, service types.ServiceConfig, errorString *strings.Builder) {
+	backup := service.Backup
+	if backup == nil {
+		return
+	}
+	if len(service.Mounts) == 0 && len(service.VolumeClaims) == 0 {
+		errorString.WriteString(fmt.Sprintf("%s backup requires mounts or volume_claims\n", prefix))
+	}
+	if backup.Schedule != "" {
+		if _, err := cron.Parse(backup.Schedule); err != nil {
+			errorString.WriteString(fmt.Sprintf("%s backup schedule: %v\n", prefix, err))
+		}
+	}
+	if backup.Retention < 0 {
+		errorString.WriteString(fmt.Sprintf("%s backup retention can't be negative\n", prefix))
+	}
+}
+
+// ValidateMaintenance -> windows set at runtime (API)
+func ValidateMaintenance(windows []types.MaintenanceWindow) error {
+	var errorString strings.Builder
+	validateMaintenance("node", windows, &errorString)
+	if errorString.String() == "" {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.TrimSpace(errorString.String()))
+}
+
+// validateDockerEndpoint -> node docker host scheme and TLS / ssh settings
+func validateDockerEndpoint(prefix string, ep types.DockerEndpoint, errorString *strings.Builder) {
+	u, err := url.Parse(ep.Host)
+	if err != nil {
+		errorString.WriteString(fmt.Sprintf("%s docker host is invalid: %v\n", prefix, err))
+		return
+	}
+
+	switch u.Scheme {
+	case "unix", "tcp":
+		if (ep.TLSCert == "") != (ep.TLSKey == "") {
+			errorString.WriteString(fmt.Sprintf("%s docker tls_cert and tls_key must be set together\n", prefix))
+		}
+		if u.Scheme == "unix" && ep.TLSCert != "" {
+			errorString.WriteString(fmt.Sprintf("%s docker TLS is supported only for tcp:// hosts\n", prefix))
+		}
+	case "ssh":
+		if u.Hostname() == "" {
+			errorString.WriteString(fmt.Sprintf("%s docker ssh host can't be empty\n", prefix))
+		}
+		if ep.TLSCert != "" || ep.TLSCACert != "" {
+			errorString.WriteString(fmt.Sprintf("%s docker TLS is not used with ssh:// hosts\n", prefix))
+		}
+	default:
+		errorString.WriteString(fmt.Sprintf(
+			"%s docker host must start with unix://, tcp:// or ssh://, got %q\n", prefix, ep.Host))
+	}
+}
+
 // LoadConfig -> for validation process
 func LoadConfig(path string) (*types.OchestratorConfig, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -215,8 +815,56 @@ func LoadConfig(path string) (*types.OchestratorConfig, error) {
 
 // Apply defaults funcs
 func applyDefaults(config *types.OchestratorConfig) {
+	if config.HostPortRange.Start == 0 && config.HostPortRange.End == 0 {
+		config.HostPortRange = types.PortRange{Start: 30000, End: 32767}
+	}
+	applyRebalancerDefaults(&config.Rebalancer)
+	applyDNSDefaults(&config.DNS)
+	applyIngressDefaults(&config.Ingress)
+	if config.Secrets.KeyFile == "" {
+		config.Secrets.KeyFile = filepath.Join(config.DataDir, "secrets.key")
+	}
+
+	// No overcommit by default
+	if config.Overcommit.CPU == 0 {
+		config.Overcommit.CPU = 1.0
+	}
+	if config.Overcommit.Memory == 0 {
+		config.Overcommit.Memory = 1.0
+	}
+	if config.MemoryPressureThreshold == 0 {
+		config.MemoryPressureThreshold = 95.0
+	}
+	if config.NodeLostGracePeriod == 0 {
+		config.NodeLostGracePeriod = 1 * time.Minute
+	}
+	if config.DrainTimeout == 0 {
+		config.DrainTimeout = 10 * time.Minute
+	}
+
+	for i := range config.Services {
+		for j := range config.Services[i].Ports {
+			if config.Services[i].Ports[j].Protocol == "" {
+				config.Services[i].Ports[j].Protocol = types.TCP
+			}
+		}
+	}
+
+	for i := range config.Volumes {
+		if config.Volumes[i].Driver == "" {
+			config.Volumes[i].Driver = types.DefaultVolumeDriver
+		}
+	}
+
+	for i := range config.Networks {
+		if config.Networks[i].Driver == "" {
+			config.Networks[i].Driver = types.DefaultNetworkDriver
+		}
+	}
+
 	for i := range config.Services {
 		applyServiceDefaults(&config.Services[i])
+		applyServiceDNS(&config.Services[i], config.DNS)
 		applyScalePolicyDefaults(&config.Services[i].ScalePolicy)
 		applyHealthCheckDefaults(config.Services[i].HealthCheck)
 		applyPredictiveScalingDefaults(config.Services[i].ScalePolicy.PredictiveScaling)
@@ -230,10 +878,85 @@ func applyServiceDefaults(svc *types.ServiceConfig) {
 		svc.ServiceType = types.ServiceTypeStateless
 	}
 
+	// Secret without env -> file in /run/secrets
+	for i := range svc.Secrets {
+		if svc.Secrets[i].Env == "" && svc.Secrets[i].Target == "" {
+			svc.Secrets[i].Target = path.Join(types.DefaultSecretsDir, svc.Secrets[i].Name)
+		}
+	}
+
 	// IF empty constraints
 	if svc.SchedulingConstraints == nil {
 		svc.SchedulingConstraints = &types.SchedulingConstraints{}
 	}
+
+	// Canonical operator names ("in" -> "In"), unknown ones are reported by validation
+	normalizeAffinityRules(svc.SchedulingConstraints.Affinity)
+	normalizeAffinityRules(svc.SchedulingConstraints.AntiAffinity)
+
+	// Exclusive cores are whole CPUs
+	if minCPU := int64(svc.Resources.ExclusiveCPUs) * 1000; minCPU > 0 {
+		if svc.Resources.CPUMilliCores < minCPU {
+			svc.Resources.CPUMilliCores = minCPU
+		}
+		if svc.Resources.Requests != nil && svc.Resources.Requests.CPUMilliCores < minCPU {
+			svc.Resources.Requests.CPUMilliCores = minCPU
+		}
+		if svc.Resources.Limits != nil && svc.Resources.Limits.CPUMilliCores < minCPU {
+			svc.Resources.Limits.CPUMilliCores = minCPU
+		}
+	}
+
+	// Single network is the first of networks
+	if svc.Network != "" && !slices.Contains(svc.Networks, svc.Network) {
+		svc.Networks = append([]string{svc.Network}, svc.Networks...)
+	}
+
+	// Claim volumes outlive service unless asked otherwise
+	if svc.VolumeReclaimPolicy == "" {
+		svc.VolumeReclaimPolicy = types.VolumeReclaimRetain
+	}
+	for i := range svc.VolumeClaims {
+		if svc.VolumeClaims[i].Driver == "" {
+			svc.VolumeClaims[i].Driver = types.DefaultVolumeDriver
+		}
+	}
+
+	// One replica disrupted at a time
+	if !svc.DisruptionBudget.MinAvailable.IsSet() && !svc.DisruptionBudget.MaxUnavailable.IsSet() {
+		svc.DisruptionBudget.MaxUnavailable = "1"
+	}
+
+	// Topology spread defaults
+	for i := range svc.TopologySpread {
+		if svc.TopologySpread[i].MaxSkew == 0 {
+			svc.TopologySpread[i].MaxSkew = 1
+		}
+		if svc.TopologySpread[i].WhenUnsatisfiable == "" {
+			svc.TopologySpread[i].WhenUnsatisfiable = types.DoNotSchedule
+		}
+	}
+
+	// Default topology -> node
+	for i := range svc.SchedulingConstraints.ServiceAffinity {
+		if svc.SchedulingConstraints.ServiceAffinity[i].TopologyKey == "" {
+			svc.SchedulingConstraints.ServiceAffinity[i].TopologyKey = types.TopologyKeyNode
+		}
+	}
+	for i := range svc.SchedulingConstraints.ServiceAntiAffinity {
+		if svc.SchedulingConstraints.ServiceAntiAffinity[i].TopologyKey == "" {
+			svc.SchedulingConstraints.ServiceAntiAffinity[i].TopologyKey = types.TopologyKeyNode
+		}
+	}
+}
+
+// Affinity operators to canonical form
+func normalizeAffinityRules(rules []types.AffinityRule) {
+	for i := range rules {
+		if op, err := types.ParseSelectorOperator(rules[i].Operator); err == nil {
+			rules[i].Operator = string(op)
+		}
+	}
 }
 
 // ScalePolicy default values
@@ -280,6 +1003,72 @@ func applyPredictiveScalingDefaults(ps *types.PredictiveScalingConfig) {
 	}
 }
 
+// DNS server default values
+func applyDNSDefaults(dc *types.DNSConfig) {
+	if dc.ListenAddr == "" {
+		dc.ListenAddr = ":53"
+	}
+	if dc.Domain == "" {
+		dc.Domain = "gorchester"
+	}
+	if dc.TTL == 0 {
+		dc.TTL = 5 * time.Second
+	}
+	for i, addr := range dc.Upstream {
+		if net.ParseIP(addr) != nil {
+			dc.Upstream[i] = net.JoinHostPort(addr, "53")
+		}
+	}
+}
+
+// Ingress default values
+func applyIngressDefaults(ic *types.IngressConfig) {
+	if ic.DrainTimeout == 0 {
+		ic.DrainTimeout = 30 * time.Second
+	}
+	if ic.RefreshInterval == 0 {
+		ic.RefreshInterval = 5 * time.Second
+	}
+	for i := range ic.Listeners {
+		l := &ic.Listeners[i]
+		if l.Protocol == "" {
+			l.Protocol = types.IngressTCP
+		}
+		if l.Balance == "" {
+			l.Balance = types.BalanceRoundRobin
+		}
+		for j := range l.Routes {
+			if l.Routes[j].Path == "" {
+				l.Routes[j].Path = "/"
+			}
+		}
+	}
+}
+
+// applyServiceDNS -> containers ask orchestrator DNS first
+func applyServiceDNS(svc *types.ServiceConfig, dc types.DNSConfig) {
+	if !dc.Enabled || !dc.Inject || dc.AdvertiseIP == "" || slices.Contains(svc.DNS, dc.AdvertiseIP) {
+		return
+	}
+	svc.DNS = append([]string{dc.AdvertiseIP}, svc.DNS...)
+}
+
+// Rebalancer default values
+func applyRebalancerDefaults(rc *types.RebalancerConfig) {
+	if rc.Interval == 0 {
+		rc.Interval = 5 * time.Minute
+	}
+	if rc.MaxMovesPerCycle == 0 {
+		rc.MaxMovesPerCycle = 1
+	}
+	if rc.MaxNodeSkew == 0 {
+		rc.MaxNodeSkew = 1
+	}
+	if rc.ReadyTimeout == 0 {
+		rc.ReadyTimeout = 2 * time.Minute
+	}
+}
+
 // HealthCheck default values
 func applyHealthCheckDefaults(hc *types.HealthCheck) {
 	if hc == nil {
@@ -0,0 +1,69 @@
+// Package core. cpuset.go -> общий пул ядер узла: незакрепленные контейнеры
+// не работают на ядрах, закрепленных за exclusive_cpus / cpu_set, и получают
+// новый cpuset, когда ядра закрепляются или освобождаются.
+package core
+
+import (
+	"context"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// isPinned -> task runs on its own cores, not in shared pool
+func isPinned(task *types.Task) bool {
+	return task.ServiceConfig != nil &&
+		(task.ServiceConfig.Resources.ExclusiveCPUs > 0 || task.ServiceConfig.Resources.CPUSet != "")
+}
+
+// syncSharedCPUs -> running unpinned containers follow shared pool of their node
+func (o *Orchestrator) syncSharedCPUs(ctx context.Context, tasks []*types.Task) {
+	pools := make(map[string]string) // nodeID -> shared pool
+	for _, task := range tasks {
+		if task.Status != types.TaskStatusRunning || task.ContainerID == "" || task.NodeID == "" || isPinned(task) {
+			continue
+		}
+
+		pool, cached := pools[task.NodeID]
+		if !cached {
+			pool = o.scheduler.SharedCPUSet(task.NodeID)
+			pools[task.NodeID] = pool
+		}
+		if pool == "" || pool == task.CPUSet {
+			continue
+		}
+
+		if err := o.runtime(task.NodeID).UpdateContainerCPUSet(ctx, task.ContainerID, pool); err != nil {
+			o.logger.Warn("failed to move container to shared cores",
+				"task_id", task.ID,
+				"node", task.NodeID,
+				"cpu_set", pool,
+				"error", err)
+			continue
+		}
+
+		task.CPUSet = pool
+		if err := o.taskStore.Update(ctx, task); err != nil {
+			o.logger.Error("failed to save shared cores of task",
+				"task_id", task.ID,
+				"error", err)
+			continue
+		}
+
+		o.logger.Debug("container moved to shared cores",
+			"task_id", task.ID,
+			"node", task.NodeID,
+			"cpu_set", pool)
+	}
+}
+
+// syncNodeSharedCPUs -> cores were pinned on node, unpinned containers leave them now
+func (o *Orchestrator) syncNodeSharedCPUs(ctx context.Context, nodeID string) {
+	tasks, err := o.taskStore.ListByNodeID(ctx, nodeID)
+	if err != nil {
+		o.logger.Error("failed to list node tasks for shared cores",
+			"node", nodeID,
+			"error", err)
+		return
+	}
+	o.syncSharedCPUs(ctx, tasks)
+}
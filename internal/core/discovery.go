@@ -0,0 +1,101 @@
+// Package core. discovery.go -> источник записей DNS обнаружения сервисов:
+// готовые задачи сервиса (running + пройденная проверка здоровья) с адресом узла и host-портами.
+package core
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/exitae337/gorchester/internal/dns"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+var _ dns.Source = (*Orchestrator)(nil)
+
+// Endpoints -> ready replicas of service for DNS, read from TaskStore on every query
+func (o *Orchestrator) Endpoints(ctx context.Context, service string) ([]dns.Endpoint, bool) {
+	svc := o.findServiceFold(service)
+	if svc == nil {
+		return nil, false
+	}
+
+	ready := o.readyTasks(ctx, svc.ServiceName)
+	endpoints := make([]dns.Endpoint, 0, len(ready))
+	for _, rt := range ready {
+		ip := net.ParseIP(rt.nodeIP)
+		if ip == nil {
+			continue
+		}
+
+		task := rt.task
+		name := task.Hostname
+		if name == "" {
+			name = task.ID[:8]
+		}
+		ports := make([]dns.Port, 0, len(task.PortMapping))
+		for _, p := range task.PortMapping {
+			ports = append(ports, dns.Port{
+				HostPort:      p.HostPort,
+				ContainerPort: p.ContainerPort,
+				Protocol:      strings.ToLower(string(p.Protocol)),
+			})
+		}
+		endpoints = append(endpoints, dns.Endpoint{Name: name, IP: ip, Ports: ports})
+	}
+	return endpoints, true
+}
+
+// readyTask -> ready task with address of its node ("" when node has no ip)
+type readyTask struct {
+	task   *types.Task
+	nodeIP string
+}
+
+// readyTasks -> ready tasks of service for DNS and ingress
+func (o *Orchestrator) readyTasks(ctx context.Context, serviceName string) []readyTask {
+	tasks, err := o.taskStore.ListByService(ctx, serviceName)
+	if err != nil {
+		o.logger.Error("failed to list ready tasks", "service", serviceName, "error", err)
+		return nil
+	}
+
+	nodeIPs := make(map[string]string)
+	ready := make([]readyTask, 0, len(tasks))
+	for _, task := range tasks {
+		if !taskReady(task) {
+			continue
+		}
+		ip, cached := nodeIPs[task.NodeID]
+		if !cached {
+			if node, err := o.scheduler.GetNode(ctx, task.NodeID); err == nil {
+				ip = node.IP
+			}
+			nodeIPs[task.NodeID] = ip
+		}
+		ready = append(ready, readyTask{task: task, nodeIP: ip})
+	}
+	return ready
+}
+
+// taskReady -> running container that passed health check (when service has one)
+func taskReady(task *types.Task) bool {
+	if task.Status != types.TaskStatusRunning || task.DesiredState != types.TaskStatusRunning {
+		return false
+	}
+	hc := task.ServiceConfig
+	if hc == nil || hc.HealthCheck == nil || hc.HealthCheck.Type == "" {
+		return true
+	}
+	return task.HealthyAt != nil
+}
+
+// findServiceFold -> service by case insensitive name (DNS names)
+func (o *Orchestrator) findServiceFold(name string) *types.ServiceConfig {
+	for i := range o.appConfig.Services {
+		if strings.EqualFold(o.appConfig.Services[i].ServiceName, name) {
+			return &o.appConfig.Services[i]
+		}
+	}
+	return nil
+}
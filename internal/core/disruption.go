@@ -0,0 +1,113 @@
+// Package core. disruption.go -> бюджет добровольных нарушений сервиса:
+// scale down, drain, обслуживание, ребалансировка и вытеснение после смены меток
+// сначала проверяют, сколько реплик можно вывести прямо сейчас.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// errDisruptionBudget -> voluntary disruption would leave service below its budget
+var errDisruptionBudget = errors.New("disruption budget exhausted")
+
+// DisruptionStatus -> budget of service and disruptions it allows right now (API)
+type DisruptionStatus struct {
+	ServiceName        string                 `json:"service_name"`
+	Budget             types.DisruptionBudget `json:"budget"`
+	Desired            int                    `json:"desired"`
+	Healthy            int                    `json:"healthy"`
+	InProgress         int                    `json:"in_progress"` // moves and stops holding the budget
+	DisruptionsAllowed int                    `json:"disruptions_allowed"`
+}
+
+// DisruptionStatus -> current budget of service from config
+func (o *Orchestrator) DisruptionStatus(ctx context.Context, serviceName string) (*DisruptionStatus, error) {
+	svc := o.findService(serviceName)
+	if svc == nil {
+		return nil, fmt.Errorf("service %s not found in config", serviceName)
+	}
+
+	desired, healthy := o.disruptionCounts(ctx, svc)
+
+	o.migrationMu.Lock()
+	inProgress := o.migrating[svc.ServiceName]
+	o.migrationMu.Unlock()
+
+	return &DisruptionStatus{
+		ServiceName:        svc.ServiceName,
+		Budget:             svc.DisruptionBudget,
+		Desired:            desired,
+		Healthy:            healthy,
+		InProgress:         inProgress,
+		DisruptionsAllowed: max(0, svc.DisruptionBudget.Allowed(desired, healthy)-inProgress),
+	}, nil
+}
+
+// reserveDisruption -> mark service as migrating if its disruption budget allows one more disruption
+func (o *Orchestrator) reserveDisruption(ctx context.Context, svc *types.ServiceConfig) bool {
+	desired, healthy := o.disruptionCounts(ctx, svc)
+
+	o.migrationMu.Lock()
+	defer o.migrationMu.Unlock()
+	if svc.DisruptionBudget.Allowed(desired, healthy)-o.migrating[svc.ServiceName] <= 0 {
+		return false
+	}
+	o.migrating[svc.ServiceName]++
+	return true
+}
+
+// disruptionCounts -> desired replicas of service and replicas running now
+func (o *Orchestrator) disruptionCounts(ctx context.Context, svc *types.ServiceConfig) (int, int) {
+	desired := o.budgetReplicas(ctx, svc)
+
+	tasks, err := o.taskStore.ListByService(ctx, svc.ServiceName)
+	if err != nil {
+		return desired, 0
+	}
+	return desired, healthyReplicas(tasks)
+}
+
+// budgetReplicas -> replicas the budget percentages are taken from
+func (o *Orchestrator) budgetReplicas(ctx context.Context, svc *types.ServiceConfig) int {
+	if svc.ServiceType != types.ServiceTypeDaemon {
+		return svc.Replicas
+	}
+	nodes, err := o.scheduler.GetNodes(ctx)
+	if err != nil {
+		return svc.Replicas
+	}
+	return svc.Replicas * len(nodes)
+}
+
+// healthyReplicas -> running replicas that are meant to run (tasks stopped in this pass already have DesiredState stopped)
+func healthyReplicas(tasks []*types.Task) int {
+	healthy := 0
+	for _, task := range tasks {
+		if task.IsRunning() && task.DesiredState == types.TaskStatusRunning {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// migrateWithinBudget -> migrateTask, waiting up to wait while budget of service is exhausted
+func (o *Orchestrator) migrateWithinBudget(ctx context.Context, move RebalanceMove, wait time.Duration) error {
+	deadline := time.Now().Add(wait)
+	for {
+		err := o.migrateTask(ctx, move)
+		if !errors.Is(err, errDisruptionBudget) || time.Now().After(deadline) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(drainRetryInterval):
+		}
+	}
+}
@@ -0,0 +1,315 @@
+// Package core. drain.go -> вывод узла из работы: cordon (без новых задач)
+// и drain (перенос задач: сначала новая реплика, ожидание health, затем остановка старой)
+// с бюджетом нарушений сервиса и таймаутом.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// DrainState -> phase of node drain
+type DrainState string
+
+const (
+	DrainStateRunning   DrainState = "running"
+	DrainStateCompleted DrainState = "completed"
+	DrainStateTimedOut  DrainState = "timed_out"
+	DrainStateCancelled DrainState = "cancelled"
+)
+
+// pause between drain passes
+const drainRetryInterval = 5 * time.Second
+
+// DrainStatus -> progress of node drain
+type DrainStatus struct {
+	NodeID     string     `json:"node_id"`
+	State      DrainState `json:"state"`
+	StartedAt  time.Time  `json:"started_at"`
+	Deadline   time.Time  `json:"deadline"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Total      int        `json:"total"`             // tasks on node when drain started
+	Migrated   int        `json:"migrated"`          // moved to other nodes
+	Stopped    int        `json:"stopped"`           // daemon tasks, stopped without replacement
+	Remaining  int        `json:"remaining"`         // still on node
+	Blocked    []string   `json:"blocked,omitempty"` // services waiting for disruption budget
+	LastError  string     `json:"last_error,omitempty"`
+}
+
+// drainRun -> running or finished drain of one node
+type drainRun struct {
+	mu     sync.Mutex
+	status DrainStatus
+	cancel context.CancelFunc
+}
+
+func (r *drainRun) update(fn func(s *DrainStatus)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(&r.status)
+}
+
+func (r *drainRun) snapshot() DrainStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := r.status
+	status.Blocked = append([]string(nil), r.status.Blocked...)
+	return status
+}
+
+// CordonNode -> stop or resume new placements, running tasks stay
+func (o *Orchestrator) CordonNode(ctx context.Context, nodeID string, cordon bool) error {
+	if err := o.scheduler.SetUnschedulable(ctx, nodeID, cordon); err != nil {
+		return err
+	}
+	o.logger.Info("node cordon changed", "node_id", nodeID, "cordoned", cordon)
+	return nil
+}
+
+// DrainNode -> cordon node and move its tasks in background, timeout 0 -> drain_timeout from config
+func (o *Orchestrator) DrainNode(ctx context.Context, nodeID string, timeout time.Duration) (*DrainStatus, error) {
+	if timeout <= 0 {
+		timeout = o.appConfig.DrainTimeout
+	}
+
+	o.drainMu.Lock()
+	defer o.drainMu.Unlock()
+
+	if run, exists := o.drains[nodeID]; exists && run.snapshot().State == DrainStateRunning {
+		return nil, fmt.Errorf("node %s is already draining", nodeID)
+	}
+
+	if err := o.scheduler.UpdateNodeStatus(ctx, nodeID, types.NodeStatusDraining); err != nil {
+		return nil, err
+	}
+	if err := o.scheduler.SetUnschedulable(ctx, nodeID, true); err != nil {
+		return nil, err
+	}
+
+	tasks, err := o.taskStore.ListByNodeID(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks of node: %w", err)
+	}
+	total := 0
+	for _, task := range tasks {
+		if task.IsActive() {
+			total++
+		}
+	}
+
+	now := time.Now()
+	drainCtx, cancel := context.WithDeadline(o.ctx, now.Add(timeout))
+	run := &drainRun{
+		cancel: cancel,
+		status: DrainStatus{
+			NodeID:    nodeID,
+			State:     DrainStateRunning,
+			StartedAt: now,
+			Deadline:  now.Add(timeout),
+			Total:     total,
+			Remaining: total,
+		},
+	}
+	o.drains[nodeID] = run
+
+	o.logger.Info("node drain started",
+		"node_id", nodeID,
+		"tasks", total,
+		"timeout", timeout)
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		defer cancel()
+		o.drain(drainCtx, nodeID, run)
+	}()
+
+	status := run.snapshot()
+	return &status, nil
+}
+
+// DrainStatus -> progress of last drain of node
+func (o *Orchestrator) DrainStatus(nodeID string) (*DrainStatus, error) {
+	o.drainMu.Lock()
+	run, exists := o.drains[nodeID]
+	o.drainMu.Unlock()
+
+	if !exists {
+		return nil, fmt.Errorf("node %s was not drained", nodeID)
+	}
+	status := run.snapshot()
+	return &status, nil
+}
+
+// ActivateNode -> cancel drain, node is ready and schedulable again
+func (o *Orchestrator) ActivateNode(ctx context.Context, nodeID string) error {
+	o.drainMu.Lock()
+	if run, exists := o.drains[nodeID]; exists {
+		run.cancel()
+	}
+	o.drainMu.Unlock()
+
+	if err := o.scheduler.UpdateNodeStatus(ctx, nodeID, types.NodeStatusReady); err != nil {
+		return err
+	}
+	return o.scheduler.SetUnschedulable(ctx, nodeID, false)
+}
+
+// drain -> passes over tasks of node until it is empty or deadline is reached
+func (o *Orchestrator) drain(ctx context.Context, nodeID string, run *drainRun) {
+	inFlight := make(map[string]bool) // taskID -> migration started
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for {
+		tasks, err := o.taskStore.ListByNodeID(ctx, nodeID)
+		if err != nil && ctx.Err() == nil {
+			run.update(func(s *DrainStatus) { s.LastError = err.Error() })
+		}
+
+		active := make([]*types.Task, 0, len(tasks))
+		daemons := make([]*types.Task, 0)
+		for _, task := range tasks {
+			if !task.IsActive() {
+				continue
+			}
+			if svc := o.findService(task.ServiceName); svc != nil && svc.ServiceType == types.ServiceTypeDaemon {
+				daemons = append(daemons, task)
+				continue
+			}
+			active = append(active, task)
+		}
+		run.update(func(s *DrainStatus) { s.Remaining = len(active) + len(daemons) })
+
+		// Daemon replica belongs to node -> stopped after everything else left
+		if len(active) == 0 && err == nil {
+			for _, task := range daemons {
+				if err := o.stopTask(ctx, task); err != nil {
+					run.update(func(s *DrainStatus) { s.LastError = err.Error() })
+					continue
+				}
+				run.update(func(s *DrainStatus) { s.Stopped++; s.Remaining-- })
+			}
+			wg.Wait()
+			o.finishDrain(nodeID, run, DrainStateCompleted)
+			return
+		}
+
+		started := 0
+		blocked := make([]string, 0)
+		for _, task := range active {
+			mu.Lock()
+			moving := inFlight[task.ID]
+			mu.Unlock()
+			if moving {
+				continue
+			}
+
+			svc := o.findService(task.ServiceName)
+			if svc == nil {
+				// Service left config -> nothing to keep available
+				if err := o.stopTask(ctx, task); err == nil {
+					run.update(func(s *DrainStatus) { s.Stopped++ })
+				}
+				continue
+			}
+
+			if !o.reserveDisruption(ctx, svc) {
+				if !containsID(blocked, svc.ServiceName) {
+					blocked = append(blocked, svc.ServiceName)
+				}
+				continue
+			}
+
+			targets, err := o.otherNodeIDs(ctx, nodeID)
+			if err != nil {
+				o.setMigrating(svc.ServiceName, false)
+				run.update(func(s *DrainStatus) { s.LastError = err.Error() })
+				continue
+			}
+
+			mu.Lock()
+			inFlight[task.ID] = true
+			mu.Unlock()
+			started++
+
+			wg.Add(1)
+			go func(task *types.Task) {
+				defer wg.Done()
+				defer o.setMigrating(task.ServiceName, false)
+
+				err := o.moveTask(ctx, RebalanceMove{
+					TaskID:      task.ID,
+					ServiceName: task.ServiceName,
+					FromNode:    nodeID,
+					TargetNodes: targets,
+					Reason:      "node drain",
+				})
+
+				mu.Lock()
+				delete(inFlight, task.ID)
+				mu.Unlock()
+
+				if err != nil {
+					o.logger.Warn("failed to move task from draining node",
+						"task_id", task.ID,
+						"service", task.ServiceName,
+						"node_id", nodeID,
+						"error", err)
+					run.update(func(s *DrainStatus) { s.LastError = err.Error() })
+					return
+				}
+				run.update(func(s *DrainStatus) { s.Migrated++ })
+			}(task)
+		}
+		run.update(func(s *DrainStatus) { s.Blocked = blocked })
+
+		if started > 0 || len(blocked) > 0 {
+			o.logger.Debug("drain pass",
+				"node_id", nodeID,
+				"started", started,
+				"blocked", blocked)
+		}
+
+		// Running moves finish or budget frees up meanwhile
+		select {
+		case <-ctx.Done():
+		case <-time.After(drainRetryInterval):
+		}
+
+		if ctx.Err() != nil {
+			wg.Wait()
+			state := DrainStateCancelled
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				state = DrainStateTimedOut
+			}
+			o.finishDrain(nodeID, run, state)
+			return
+		}
+	}
+}
+
+// finishDrain -> final state of drain, node stays cordoned until activated
+func (o *Orchestrator) finishDrain(nodeID string, run *drainRun, state DrainState) {
+	now := time.Now()
+	run.update(func(s *DrainStatus) {
+		s.State = state
+		s.FinishedAt = &now
+		if state == DrainStateCompleted {
+			s.Blocked = nil
+		}
+	})
+
+	status := run.snapshot()
+	o.logger.Info("node drain finished",
+		"node_id", nodeID,
+		"state", state,
+		"migrated", status.Migrated,
+		"stopped", status.Stopped,
+		"remaining", status.Remaining)
+}
@@ -0,0 +1,141 @@
+// Package core. eviction.go -> вытеснение задач, превысивших запрошенные ресурсы
+// (размер writable-слоя контейнера больше disk_bytes) и вытеснение по QoS-классам
+// при нехватке памяти на узле.
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// checkEphemeralStorage -> evict tasks whose writable layer exceeds requested disk
+func (o *Orchestrator) checkEphemeralStorage(ctx context.Context, tasks []*types.Task) {
+	for _, task := range tasks {
+		if task.ServiceConfig == nil || task.ServiceConfig.Resources.DiskBytes <= 0 || task.ContainerID == "" {
+			continue
+		}
+
+		used, err := o.runtime(task.NodeID).GetContainerDiskUsage(ctx, task.ContainerID)
+		if err != nil {
+			o.logger.Debug("failed to get container disk usage",
+				"task_id", task.ID,
+				"error", err)
+			continue
+		}
+
+		limit := task.ServiceConfig.Resources.DiskBytes
+		if used <= limit {
+			continue
+		}
+
+		o.logger.Warn("task exceeded ephemeral storage request, evicting",
+			"task_id", task.ID,
+			"service", task.ServiceName,
+			"used_bytes", used,
+			"limit_bytes", limit)
+
+		o.evictTask(ctx, task, fmt.Sprintf("ephemeral storage usage %d exceeds request %d", used, limit))
+	}
+}
+
+// evictTask -> stop task and mark it failed, reconcile creates replacement
+func (o *Orchestrator) evictTask(ctx context.Context, task *types.Task, reason string) {
+	if err := o.stopTask(ctx, task); err != nil {
+		o.logger.Error("failed to evict task",
+			"task_id", task.ID,
+			"error", err)
+		return
+	}
+
+	task.Status = types.TaskStatusFailed
+	task.Error = "evicted: " + reason
+	if err := o.taskStore.Update(ctx, task); err != nil {
+		o.logger.Error("failed to update evicted task",
+			"task_id", task.ID,
+			"error", err)
+	}
+}
+
+// relieveMemoryPressure -> evict tasks by QoS class until node memory usage is below threshold
+func (o *Orchestrator) relieveMemoryPressure(ctx context.Context, memoryUsage map[string]int64) {
+	threshold := o.appConfig.MemoryPressureThreshold
+	if threshold <= 0 || len(memoryUsage) == 0 {
+		return
+	}
+
+	tasks, err := o.taskStore.ListByStatus(ctx, types.TaskStatusRunning)
+	if err != nil {
+		o.logger.Error("failed to list running tasks for memory pressure", "error", err)
+		return
+	}
+
+	tasksByNode := make(map[string][]*types.Task)
+	for _, task := range tasks {
+		if _, measured := memoryUsage[task.ID]; measured && task.NodeID != "" && task.ServiceConfig != nil {
+			tasksByNode[task.NodeID] = append(tasksByNode[task.NodeID], task)
+		}
+	}
+
+	nodes, err := o.scheduler.GetNodes(ctx)
+	if err != nil {
+		o.logger.Error("failed to get nodes for memory pressure", "error", err)
+		return
+	}
+
+	for _, node := range nodes {
+		nodeTasks := tasksByNode[node.ID]
+		if len(nodeTasks) == 0 {
+			continue
+		}
+
+		node.Mu.RLock()
+		capacity := node.Resources.Memory
+		node.Mu.RUnlock()
+
+		// Physical memory, overcommit does not count here
+		allowed := int64(float64(capacity) * threshold / 100)
+		var used int64
+		for _, task := range nodeTasks {
+			used += memoryUsage[task.ID]
+		}
+		if used <= allowed {
+			continue
+		}
+
+		o.logger.Warn("node under memory pressure",
+			"node_id", node.ID,
+			"used_bytes", used,
+			"allowed_bytes", allowed)
+
+		// best-effort -> burstable (most over request first) -> guaranteed
+		sort.SliceStable(nodeTasks, func(i, j int) bool {
+			iRank := nodeTasks[i].ServiceConfig.Resources.QoSClass().EvictionRank()
+			jRank := nodeTasks[j].ServiceConfig.Resources.QoSClass().EvictionRank()
+			if iRank != jRank {
+				return iRank < jRank
+			}
+			iOver := memoryUsage[nodeTasks[i].ID] - nodeTasks[i].ServiceConfig.Resources.MemoryRequest()
+			jOver := memoryUsage[nodeTasks[j].ID] - nodeTasks[j].ServiceConfig.Resources.MemoryRequest()
+			return iOver > jOver
+		})
+
+		for _, task := range nodeTasks {
+			if used <= allowed {
+				break
+			}
+
+			o.logger.Warn("evicting task under memory pressure",
+				"task_id", task.ID,
+				"service", task.ServiceName,
+				"node_id", node.ID,
+				"qos", task.ServiceConfig.Resources.QoSClass(),
+				"memory_bytes", memoryUsage[task.ID])
+
+			o.evictTask(ctx, task, fmt.Sprintf("memory pressure on node %s", node.ID))
+			used -= memoryUsage[task.ID]
+		}
+	}
+}
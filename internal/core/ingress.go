@@ -0,0 +1,86 @@
+// Package core. ingress.go -> источник реплик для встроенного балансировщика
+// и уведомления о событиях задач: готовность, сбой, остановка с дренажом соединений.
+package core
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/ingress"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+var _ ingress.Source = (*Orchestrator)(nil)
+
+// Balancer -> ingress proxy in front of service replicas
+type Balancer interface {
+	// Refresh -> ready replicas of service may have changed
+	Refresh(service string)
+	// Drain -> task is stopping, returns when its connections are finished or closed (drain_timeout or ctx)
+	Drain(ctx context.Context, service, taskID string)
+}
+
+// SetBalancer -> set before Start, task events are sent to balancer
+func (o *Orchestrator) SetBalancer(b Balancer) {
+	o.balancer = b
+}
+
+// Backends -> ready replicas publishing tcp container port, node without ip -> local host
+func (o *Orchestrator) Backends(ctx context.Context, service string, port int) ([]ingress.Backend, bool) {
+	if o.findService(service) == nil {
+		return nil, false
+	}
+
+	ready := o.readyTasks(ctx, service)
+	backends := make([]ingress.Backend, 0, len(ready))
+	for _, rt := range ready {
+		ip := rt.nodeIP
+		if ip == "" {
+			ip = "127.0.0.1"
+		}
+		for _, p := range rt.task.PortMapping {
+			if p.ContainerPort == port && p.Protocol == types.TCP && p.HostPort > 0 {
+				backends = append(backends, ingress.Backend{
+					TaskID: rt.task.ID,
+					Addr:   net.JoinHostPort(ip, strconv.Itoa(p.HostPort)),
+				})
+				break
+			}
+		}
+	}
+	return backends, true
+}
+
+// notifyBalancer -> task of service became ready or failed
+func (o *Orchestrator) notifyBalancer(service string) {
+	if o.balancer != nil {
+		o.balancer.Refresh(service)
+	}
+}
+
+// drainDeadlineKey -> deadline shared by all drains started with context
+type drainDeadlineKey struct{}
+
+// withDrainDeadline -> tasks stopped with returned context wait for their connections up to drain_timeout in total
+func (o *Orchestrator) withDrainDeadline(ctx context.Context) context.Context {
+	if o.balancer == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, drainDeadlineKey{}, time.Now().Add(o.appConfig.Ingress.DrainTimeout))
+}
+
+// drainBalancer -> stopping task gets no new connections, open ones finish first
+func (o *Orchestrator) drainBalancer(ctx context.Context, task *types.Task) {
+	if o.balancer == nil || task.Status != types.TaskStatusRunning {
+		return
+	}
+	// Deadline only limits waiting, container calls keep using ctx
+	if deadline, ok := ctx.Value(drainDeadlineKey{}).(time.Time); ok {
+		drainCtx, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
+		ctx = drainCtx
+	}
+	o.balancer.Drain(ctx, task.ServiceName, task.ID)
+}
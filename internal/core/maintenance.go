@@ -0,0 +1,266 @@
+// Package core. maintenance.go -> плановые окна обслуживания узлов:
+// перед началом окна узел выводится из работы (cordon + drain), после окончания снова ready.
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/cron"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// how often windows are checked
+const maintenanceInterval = 30 * time.Second
+
+// MaintenanceState -> phase of node relative to its windows
+type MaintenanceState string
+
+const (
+	MaintenanceStateScheduled MaintenanceState = "scheduled"      // next window is ahead
+	MaintenanceStateDraining  MaintenanceState = "draining"       // window starts soon, tasks are moved
+	MaintenanceStateActive    MaintenanceState = "in_maintenance" // window is open
+)
+
+// MaintenanceStatus -> windows of node and current phase (API)
+type MaintenanceStatus struct {
+	State     MaintenanceState          `json:"state"`
+	Windows   []MaintenanceWindowStatus `json:"windows"`
+	NextStart *time.Time                `json:"next_start,omitempty"`
+	NextEnd   *time.Time                `json:"next_end,omitempty"`
+}
+
+// MaintenanceWindowStatus -> one window with its next occurrence
+type MaintenanceWindowStatus struct {
+	Schedule    string     `json:"schedule"`
+	Duration    string     `json:"duration"`
+	DrainBefore string     `json:"drain_before"`
+	NextStart   *time.Time `json:"next_start,omitempty"`
+}
+
+// maintenanceWindow -> window with parsed schedule
+type maintenanceWindow struct {
+	types.MaintenanceWindow
+	schedule *cron.Schedule
+}
+
+// nodeMaintenance -> windows of node and window the orchestrator is acting on
+type nodeMaintenance struct {
+	windows []maintenanceWindow
+
+	// drained by maintenance, activated at windowEnd
+	active    bool
+	windowEnd time.Time
+}
+
+// SetMaintenance -> replace windows of node, empty list ends maintenance in progress
+func (o *Orchestrator) SetMaintenance(ctx context.Context, nodeID string, windows []types.MaintenanceWindow) error {
+	if _, err := o.scheduler.GetNode(ctx, nodeID); err != nil {
+		return err
+	}
+
+	parsed, err := parseMaintenance(windows)
+	if err != nil {
+		return err
+	}
+
+	o.maintenanceMu.Lock()
+	m, exists := o.maintenance[nodeID]
+	if !exists {
+		m = &nodeMaintenance{}
+		o.maintenance[nodeID] = m
+	}
+	m.windows = parsed
+	o.maintenanceMu.Unlock()
+
+	o.logger.Info("node maintenance windows updated", "node_id", nodeID, "windows", len(windows))
+
+	// Window may start or end right now
+	o.checkMaintenance(ctx)
+	return nil
+}
+
+// MaintenanceStatus -> nil when node has no windows
+func (o *Orchestrator) MaintenanceStatus(nodeID string) *MaintenanceStatus {
+	o.maintenanceMu.Lock()
+	defer o.maintenanceMu.Unlock()
+
+	m, exists := o.maintenance[nodeID]
+	if !exists || (len(m.windows) == 0 && !m.active) {
+		return nil
+	}
+	status := o.maintenancePhase(m, time.Now())
+	return &status
+}
+
+// forgetMaintenance -> node left cluster
+func (o *Orchestrator) forgetMaintenance(nodeID string) {
+	o.maintenanceMu.Lock()
+	defer o.maintenanceMu.Unlock()
+	delete(o.maintenance, nodeID)
+}
+
+// Fifth Loop: maintenanceLoop -> drain nodes before their windows, activate after
+func (o *Orchestrator) maintenanceLoop() {
+	defer o.wg.Done()
+	ticker := time.NewTicker(maintenanceInterval)
+	defer ticker.Stop()
+
+	o.logger.Info("maintenance loop started", "interval", maintenanceInterval)
+
+	for {
+		select {
+		case <-o.ctx.Done():
+			o.logger.Info("maintenance loop stopped")
+			return
+		case <-ticker.C:
+			o.checkMaintenance(o.ctx)
+		}
+	}
+}
+
+// checkMaintenance -> start drain when window is near, activate node when window is over
+func (o *Orchestrator) checkMaintenance(ctx context.Context) {
+	now := time.Now()
+
+	type action struct {
+		nodeID   string
+		drain    bool
+		timeout  time.Duration
+		activate bool
+	}
+	actions := make([]action, 0)
+
+	o.maintenanceMu.Lock()
+	for nodeID, m := range o.maintenance {
+		status := o.maintenancePhase(m, now)
+
+		if m.active {
+			// Overlapping windows -> stay in maintenance until the last one ends
+			if status.State == MaintenanceStateActive && status.NextEnd != nil && status.NextEnd.After(m.windowEnd) {
+				m.windowEnd = *status.NextEnd
+			}
+			if !now.Before(m.windowEnd) || len(m.windows) == 0 {
+				m.active = false
+				actions = append(actions, action{nodeID: nodeID, activate: true})
+			}
+			continue
+		}
+
+		if status.State != MaintenanceStateDraining && status.State != MaintenanceStateActive {
+			continue
+		}
+
+		// Drain until window opens, window already open -> drain_timeout
+		var timeout time.Duration
+		if status.NextStart != nil && status.NextStart.After(now) {
+			timeout = status.NextStart.Sub(now)
+		}
+		m.active = true
+		m.windowEnd = *status.NextEnd
+		actions = append(actions, action{nodeID: nodeID, drain: true, timeout: timeout})
+	}
+	o.maintenanceMu.Unlock()
+
+	for _, a := range actions {
+		switch {
+		case a.drain:
+			o.logger.Info("maintenance window is near, draining node",
+				"node_id", a.nodeID,
+				"drain_timeout", a.timeout)
+			if _, err := o.DrainNode(ctx, a.nodeID, a.timeout); err != nil {
+				// Drained by hand or gone -> not ours to activate
+				o.logger.Warn("failed to drain node for maintenance", "node_id", a.nodeID, "error", err)
+				o.maintenanceMu.Lock()
+				if m, exists := o.maintenance[a.nodeID]; exists {
+					m.active = false
+				}
+				o.maintenanceMu.Unlock()
+			}
+		case a.activate:
+			o.logger.Info("maintenance window is over, activating node", "node_id", a.nodeID)
+			if err := o.ActivateNode(ctx, a.nodeID); err != nil {
+				o.logger.Error("failed to activate node after maintenance", "node_id", a.nodeID, "error", err)
+			}
+		}
+	}
+}
+
+// maintenancePhase -> nearest window of node at now (maintenanceMu is held)
+func (o *Orchestrator) maintenancePhase(m *nodeMaintenance, now time.Time) MaintenanceStatus {
+	status := MaintenanceStatus{
+		State:   MaintenanceStateScheduled,
+		Windows: make([]MaintenanceWindowStatus, 0, len(m.windows)),
+	}
+	// Drained by maintenance -> at least draining until window end, open window raises it
+	if m.active {
+		status.State = MaintenanceStateDraining
+		end := m.windowEnd
+		status.NextEnd = &end
+	}
+
+	rank := map[MaintenanceState]int{
+		MaintenanceStateScheduled: 0,
+		MaintenanceStateDraining:  1,
+		MaintenanceStateActive:    2,
+	}
+
+	for _, w := range m.windows {
+		lead := w.DrainBefore
+		if lead == 0 {
+			lead = o.appConfig.DrainTimeout
+		}
+
+		// First start whose window is not over yet
+		start := w.schedule.Next(now.Add(-w.Duration))
+		ws := MaintenanceWindowStatus{
+			Schedule:    w.Schedule,
+			Duration:    w.Duration.String(),
+			DrainBefore: lead.String(),
+		}
+		if start.IsZero() {
+			status.Windows = append(status.Windows, ws)
+			continue
+		}
+		end := start.Add(w.Duration)
+		ws.NextStart = &start
+		status.Windows = append(status.Windows, ws)
+
+		state := MaintenanceStateScheduled
+		switch {
+		case !start.After(now):
+			state = MaintenanceStateActive
+		case !start.Add(-lead).After(now):
+			state = MaintenanceStateDraining
+		}
+
+		// Most urgent window wins, same phase -> earliest start
+		if rank[state] > rank[status.State] ||
+			(rank[state] == rank[status.State] && (status.NextStart == nil || start.Before(*status.NextStart))) {
+			status.State = state
+			status.NextStart = &start
+			if !m.active || end.After(*status.NextEnd) {
+				status.NextEnd = &end
+			}
+		}
+	}
+
+	return status
+}
+
+// parseMaintenance -> windows with parsed cron schedules
+func parseMaintenance(windows []types.MaintenanceWindow) ([]maintenanceWindow, error) {
+	parsed := make([]maintenanceWindow, 0, len(windows))
+	for i, w := range windows {
+		schedule, err := cron.Parse(w.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance[%d]: %w", i, err)
+		}
+		if w.Duration <= 0 {
+			return nil, fmt.Errorf("maintenance[%d]: duration must be positive", i)
+		}
+		parsed = append(parsed, maintenanceWindow{MaintenanceWindow: w, schedule: schedule})
+	}
+	return parsed, nil
+}
@@ -0,0 +1,157 @@
+// Package core. networks.go -> управляемые сети: сети задачи с алиасом по имени сервиса,
+// собственная сеть сервиса без объявленных сетей (сервисы без общей сети изолированы),
+// удаление сетей, которые на узле больше не нужны ни одной задаче.
+package core
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// how often unused networks are looked for on nodes
+const networkGCInterval = 5 * time.Minute
+
+// NetworkStatus -> network and services attached to it (API)
+type NetworkStatus struct {
+	types.NetworkConfig
+	Implicit bool     `json:"implicit"` // Own network of service without networks
+	Services []string `json:"services"`
+}
+
+// taskNetworks -> networks of service container, nil when network_mode is set
+func (o *Orchestrator) taskNetworks(svc *types.ServiceConfig, hostname string) []types.TaskNetwork {
+	if svc.NetworkMode != "" {
+		return nil
+	}
+
+	// Service name resolves to its replicas, stateful replica also by its hostname
+	aliases := []string{svc.ServiceName}
+	if hostname != "" {
+		aliases = append(aliases, hostname)
+	}
+
+	if len(svc.Networks) == 0 {
+		return []types.TaskNetwork{{
+			Name:    types.ImplicitNetworkName(svc.ServiceName),
+			Driver:  types.DefaultNetworkDriver,
+			Labels:  map[string]string{"gorchester.service": svc.ServiceName},
+			Aliases: aliases,
+		}}
+	}
+
+	networks := make([]types.TaskNetwork, 0, len(svc.Networks))
+	for _, name := range svc.Networks {
+		nc := o.findNetwork(name)
+		if nc == nil {
+			continue
+		}
+		networks = append(networks, types.TaskNetwork{
+			Name:     nc.Name,
+			Driver:   nc.Driver,
+			Subnet:   nc.Subnet,
+			Internal: nc.Internal,
+			Labels:   nc.Labels,
+			Aliases:  aliases,
+		})
+	}
+	return networks
+}
+
+// collectNetworks -> remove managed networks no active task on the node is attached to
+func (o *Orchestrator) collectNetworks(ctx context.Context, tasks []*types.Task) {
+	if time.Since(o.lastNetworkGC) < networkGCInterval {
+		return
+	}
+	o.lastNetworkGC = time.Now()
+
+	nodes, err := o.scheduler.GetNodes(ctx)
+	if err != nil {
+		return
+	}
+
+	needed := make(map[string]map[string]bool, len(nodes))
+	for _, task := range tasks {
+		if !task.IsActive() {
+			continue
+		}
+		if needed[task.NodeID] == nil {
+			needed[task.NodeID] = make(map[string]bool)
+		}
+		for _, n := range task.Networks {
+			needed[task.NodeID][n.Name] = true
+		}
+	}
+
+	for _, node := range nodes {
+		if node.Status != types.NodeStatusReady {
+			continue
+		}
+		names, err := o.runtime(node.ID).ListNetworks(ctx)
+		if err != nil {
+			o.logger.Debug("failed to list networks", "node", node.ID, "error", err)
+			continue
+		}
+		for _, name := range names {
+			if needed[node.ID][name] {
+				continue
+			}
+			// Network with containers is refused by daemon -> next round
+			if err := o.runtime(node.ID).RemoveNetwork(ctx, name); err != nil {
+				o.logger.Debug("network still in use", "node", node.ID, "network", name, "error", err)
+				continue
+			}
+			o.logger.Info("unused network removed", "node", node.ID, "network", name)
+		}
+	}
+}
+
+// ListNetworks -> declared and implicit networks with their services (API Method)
+func (o *Orchestrator) ListNetworks() []NetworkStatus {
+	result := make([]NetworkStatus, 0, len(o.appConfig.Networks))
+	index := make(map[string]int, len(o.appConfig.Networks))
+	for _, nc := range o.appConfig.Networks {
+		index[nc.Name] = len(result)
+		result = append(result, NetworkStatus{NetworkConfig: nc, Services: []string{}})
+	}
+
+	for i := range o.appConfig.Services {
+		svc := &o.appConfig.Services[i]
+		if svc.NetworkMode != "" {
+			continue
+		}
+		if len(svc.Networks) == 0 {
+			result = append(result, NetworkStatus{
+				NetworkConfig: types.NetworkConfig{
+					Name:   types.ImplicitNetworkName(svc.ServiceName),
+					Driver: types.DefaultNetworkDriver,
+				},
+				Implicit: true,
+				Services: []string{svc.ServiceName},
+			})
+			continue
+		}
+		for _, name := range svc.Networks {
+			if i, exists := index[name]; exists {
+				result[i].Services = append(result[i].Services, svc.ServiceName)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// findNetwork -> declared network by name
+func (o *Orchestrator) findNetwork(name string) *types.NetworkConfig {
+	for i := range o.appConfig.Networks {
+		if o.appConfig.Networks[i].Name == name {
+			return &o.appConfig.Networks[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,370 @@
+// Package core. nodes.go -> узлы кластера со стороны оркестратора:
+// добавление, изменение (метки, ёмкость) и удаление узлов во время работы,
+// потерянные узлы (grace period, замена задач, удаление устаревших контейнеров после возвращения узла).
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// handleLostNodes -> tasks of nodes NotReady longer than grace period are lost and replaced,
+// containers of lost tasks are removed when node is back
+func (o *Orchestrator) handleLostNodes(ctx context.Context, tasks []*types.Task) {
+	nodes, err := o.scheduler.GetNodes(ctx)
+	if err != nil {
+		o.logger.Error("failed to get nodes for lost node check", "error", err)
+		return
+	}
+
+	grace := o.appConfig.NodeLostGracePeriod
+	lost := make(map[string]bool)
+	ready := make(map[string]bool)
+	reported := make(map[string][]types.ReportedContainer) // nodeID -> containers from agent
+	for _, node := range nodes {
+		node.Mu.RLock()
+		switch node.Status {
+		case types.NodeStatusNotReady:
+			if node.NotReadySince != nil && time.Since(*node.NotReadySince) >= grace {
+				lost[node.ID] = true
+			}
+		case types.NodeStatusReady:
+			ready[node.ID] = true
+			if node.Report != nil {
+				reported[node.ID] = node.Report.Containers
+			}
+		}
+		node.Mu.RUnlock()
+	}
+
+	for _, task := range tasks {
+		switch {
+		case lost[task.NodeID] && task.IsActive():
+			o.markTaskNodeLost(ctx, task, grace)
+		case ready[task.NodeID] && task.Status == types.TaskStatusNodeLost:
+			o.removeStaleContainers(ctx, task, reported[task.NodeID])
+		}
+	}
+}
+
+// markTaskNodeLost -> task is not counted anymore, scaling creates replacement on other node
+func (o *Orchestrator) markTaskNodeLost(ctx context.Context, task *types.Task, grace time.Duration) {
+	o.logger.Warn("node lost, rescheduling task",
+		"task_id", task.ID,
+		"service", task.ServiceName,
+		"node_id", task.NodeID,
+		"grace_period", grace)
+
+	if err := o.scheduler.ReleaseNodeResources(ctx, task.NodeID, task); err != nil {
+		o.logger.Error("failed to release resources of lost task",
+			"task_id", task.ID,
+			"node_id", task.NodeID,
+			"error", err)
+	}
+
+	// Container may still run on node -> removed when node is back
+	task.DesiredState = types.TaskStatusStopped
+	task.Error = fmt.Sprintf("node %s is not ready for more than %s", task.NodeID, grace)
+	task.UpdateTask(types.TaskStatusNodeLost)
+	if err := o.taskStore.Update(ctx, task); err != nil {
+		o.logger.Error("failed to update task on lost node",
+			"task_id", task.ID,
+			"error", err)
+	}
+}
+
+// removeStaleContainers -> node is back, container of lost task duplicates its replacement
+func (o *Orchestrator) removeStaleContainers(ctx context.Context, task *types.Task, reported []types.ReportedContainer) {
+	containers := make([]string, 0, 1)
+	if task.ContainerID != "" {
+		containers = append(containers, task.ContainerID)
+	}
+	// Agent reports containers by task label, ID may be unknown (node lost during create)
+	for _, c := range reported {
+		if c.TaskID == task.ID && c.ID != task.ContainerID {
+			containers = append(containers, c.ID)
+		}
+	}
+
+	for _, containerID := range containers {
+		if err := o.runtime(task.NodeID).RemoveContainer(ctx, containerID); err != nil {
+			o.logger.Warn("failed to remove stale container of lost task",
+				"task_id", task.ID,
+				"node_id", task.NodeID,
+				"container", containerID,
+				"error", err)
+			return
+		}
+		o.logger.Info("stale container of lost task removed",
+			"task_id", task.ID,
+			"service", task.ServiceName,
+			"node_id", task.NodeID,
+			"container", containerID)
+	}
+
+	task.UpdateTask(types.TaskStatusStopped)
+	if err := o.taskStore.Update(ctx, task); err != nil {
+		o.logger.Error("failed to update lost task",
+			"task_id", task.ID,
+			"error", err)
+	}
+}
+
+// AddNode -> register node at runtime, connect to its Docker endpoint
+func (o *Orchestrator) AddNode(ctx context.Context, nc types.NodeConfig) (*types.Node, error) {
+	node := types.NewNode(nc)
+	if err := o.scheduler.RegisterNode(ctx, node); err != nil {
+		return nil, err
+	}
+
+	if nc.Docker.IsRemote() {
+		if err := o.runtimes.AddNode(nc.ID, nc.Docker); err != nil {
+			o.logger.Error("failed to connect to node docker endpoint",
+				"node_id", nc.ID,
+				"host", nc.Docker.Host,
+				"error", err)
+			o.scheduler.UpdateNodeStatus(ctx, nc.ID, types.NodeStatusNotReady)
+		}
+	}
+
+	if len(nc.Maintenance) > 0 {
+		if err := o.SetMaintenance(ctx, nc.ID, nc.Maintenance); err != nil {
+			o.logger.Error("failed to set maintenance windows of node", "node_id", nc.ID, "error", err)
+		}
+	}
+
+	o.logger.Info("node added", "node_id", node.ID, "hostname", node.Hostname)
+	return node, nil
+}
+
+// NodeUpdateResult -> tasks on node that do not fit it after update
+type NodeUpdateResult struct {
+	NodeID     string                `json:"node_id"`
+	Violations []ConstraintViolation `json:"violations"`
+	Evicting   bool                  `json:"evicting"` // violating tasks are being moved away
+}
+
+// ConstraintViolation -> task placed on node its constraints do not allow anymore
+type ConstraintViolation struct {
+	TaskID      string `json:"task_id"`
+	ServiceName string `json:"service_name"`
+	Reason      string `json:"reason"`
+}
+
+// UpdateNode -> change labels and capacity, re-check required affinity of tasks on node
+func (o *Orchestrator) UpdateNode(ctx context.Context, nodeID string, update types.NodeUpdate, evict bool) (*NodeUpdateResult, error) {
+	if err := o.scheduler.UpdateNode(ctx, nodeID, update); err != nil {
+		return nil, err
+	}
+
+	node, err := o.scheduler.GetNode(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	node.Mu.RLock()
+	labels := node.Labels
+	node.Mu.RUnlock()
+
+	tasks, err := o.taskStore.ListByNodeID(ctx, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks of node: %w", err)
+	}
+
+	result := &NodeUpdateResult{NodeID: nodeID, Violations: make([]ConstraintViolation, 0)}
+	violating := make([]*types.Task, 0)
+	for _, task := range tasks {
+		if !task.IsActive() || task.ServiceConfig == nil {
+			continue
+		}
+		if task.ServiceConfig.SchedulingConstraints.MatchesRequired(labels) {
+			continue
+		}
+		result.Violations = append(result.Violations, ConstraintViolation{
+			TaskID:      task.ID,
+			ServiceName: task.ServiceName,
+			Reason:      "required affinity no longer matches node labels",
+		})
+		violating = append(violating, task)
+	}
+
+	if len(violating) > 0 {
+		o.logger.Warn("tasks do not match updated node",
+			"node_id", nodeID,
+			"violations", len(violating),
+			"evict", evict)
+	}
+
+	if evict && len(violating) > 0 {
+		result.Evicting = true
+		o.wg.Add(1)
+		go func() {
+			defer o.wg.Done()
+			o.evictViolating(nodeID, violating)
+		}()
+	}
+
+	return result, nil
+}
+
+// evictViolating -> surge violating tasks to nodes that match them, stop if there is no room
+func (o *Orchestrator) evictViolating(nodeID string, tasks []*types.Task) {
+	ctx := o.ctx
+	for _, task := range tasks {
+		targets, err := o.otherNodeIDs(ctx, nodeID)
+		if err != nil {
+			o.logger.Error("failed to get nodes for eviction", "error", err)
+			return
+		}
+
+		err = o.migrateWithinBudget(ctx, RebalanceMove{
+			TaskID:      task.ID,
+			ServiceName: task.ServiceName,
+			FromNode:    nodeID,
+			TargetNodes: targets,
+			Reason:      "node labels changed",
+		}, o.appConfig.DrainTimeout)
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, errDisruptionBudget) {
+			o.logger.Warn("violating task left on node, disruption budget exhausted",
+				"task_id", task.ID,
+				"service", task.ServiceName,
+				"node_id", nodeID)
+			continue
+		}
+
+		// No room elsewhere -> stop, still within budget
+		svc := o.findService(task.ServiceName)
+		if svc == nil || !o.reserveDisruption(ctx, svc) {
+			o.logger.Warn("failed to migrate violating task, disruption budget forbids stopping it",
+				"task_id", task.ID,
+				"service", task.ServiceName,
+				"error", err)
+			continue
+		}
+		o.logger.Warn("failed to migrate violating task, stopping it",
+			"task_id", task.ID,
+			"service", task.ServiceName,
+			"node_id", nodeID,
+			"error", err)
+		if err := o.stopTask(ctx, task); err != nil {
+			o.logger.Error("failed to stop violating task",
+				"task_id", task.ID,
+				"error", err)
+		}
+		o.setMigrating(svc.ServiceName, false)
+	}
+}
+
+// RemoveNode -> no new tasks on node, migrate its tasks in background, then unregister it
+func (o *Orchestrator) RemoveNode(ctx context.Context, nodeID string) error {
+	o.removingMu.Lock()
+	defer o.removingMu.Unlock()
+
+	if o.removingNodes[nodeID] {
+		return fmt.Errorf("node %s is already being removed", nodeID)
+	}
+	if err := o.scheduler.SetUnschedulable(ctx, nodeID, true); err != nil {
+		return err
+	}
+	o.removingNodes[nodeID] = true
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		o.evacuateAndRemove(nodeID)
+	}()
+	return nil
+}
+
+// evacuateAndRemove -> surge every active task to other nodes, then forget node
+func (o *Orchestrator) evacuateAndRemove(nodeID string) {
+	ctx := o.ctx
+	defer func() {
+		o.removingMu.Lock()
+		delete(o.removingNodes, nodeID)
+		o.removingMu.Unlock()
+	}()
+
+	tasks, err := o.taskStore.ListByNodeID(ctx, nodeID)
+	if err != nil {
+		o.logger.Error("failed to list tasks of removed node", "node_id", nodeID, "error", err)
+		return
+	}
+
+	for _, task := range tasks {
+		// Lost task was replaced already, its container leaves with node
+		if task.Status == types.TaskStatusNodeLost {
+			task.UpdateTask(types.TaskStatusStopped)
+			if err := o.taskStore.Update(ctx, task); err != nil {
+				o.logger.Error("failed to update lost task of removed node",
+					"task_id", task.ID,
+					"error", err)
+			}
+			continue
+		}
+		if !task.IsActive() {
+			continue
+		}
+
+		targets, err := o.otherNodeIDs(ctx, nodeID)
+		if err != nil {
+			o.logger.Error("failed to get nodes for migration", "error", err)
+			return
+		}
+
+		err = o.migrateWithinBudget(ctx, RebalanceMove{
+			TaskID:      task.ID,
+			ServiceName: task.ServiceName,
+			FromNode:    nodeID,
+			TargetNodes: targets,
+			Reason:      "node removed",
+		}, o.appConfig.DrainTimeout)
+		if err == nil {
+			continue
+		}
+
+		// No room elsewhere or budget still exhausted after drain_timeout -> stop anyway,
+		// node is leaving, reconcile places replica when it can
+		o.logger.Warn("failed to migrate task of removed node, stopping it",
+			"task_id", task.ID,
+			"service", task.ServiceName,
+			"node_id", nodeID,
+			"error", err)
+		if err := o.stopTask(ctx, task); err != nil {
+			o.logger.Error("failed to stop task of removed node",
+				"task_id", task.ID,
+				"error", err)
+		}
+	}
+
+	if err := o.scheduler.UnregisterNode(ctx, nodeID); err != nil {
+		o.logger.Error("failed to unregister node", "node_id", nodeID, "error", err)
+		return
+	}
+	o.runtimes.RemoveNode(nodeID)
+	o.forgetMaintenance(nodeID)
+	o.releaseNodeVolumes(ctx, nodeID)
+
+	o.logger.Info("node removed", "node_id", nodeID)
+}
+
+// otherNodeIDs -> migration targets: every node except the one being emptied
+func (o *Orchestrator) otherNodeIDs(ctx context.Context, nodeID string) ([]string, error) {
+	nodes, err := o.scheduler.GetNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if node.ID != nodeID {
+			targets = append(targets, node.ID)
+		}
+	}
+	return targets, nil
+}
@@ -4,6 +4,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/exitae337/gorchester/internal/client"
 	"github.com/exitae337/gorchester/internal/metrics"
+	"github.com/exitae337/gorchester/internal/secrets"
 	"github.com/exitae337/gorchester/internal/types"
 	"github.com/google/uuid"
 )
@@ -27,6 +29,9 @@ type Scheduler interface {
 	// GetNodes -> all applyable Nodes
 	GetNodes(ctx context.Context) ([]*types.Node, error)
 
+	// GetNode -> Node by ID
+	GetNode(ctx context.Context, nodeID string) (*types.Node, error)
+
 	// RegisterNode -> New Node in Cluster
 	RegisterNode(ctx context.Context, node *types.Node) error
 
@@ -38,6 +43,18 @@ type Scheduler interface {
 
 	// Release Node Resources
 	ReleaseNodeResources(ctx context.Context, nodeID string, task *types.Task) error
+
+	// FilterNodes -> nodes where task can be placed now (nothing is reserved)
+	FilterNodes(ctx context.Context, task *types.Task, nodes []*types.Node) ([]*types.Node, error)
+
+	// SetUnschedulable -> stop or resume new placements on node
+	SetUnschedulable(ctx context.Context, nodeID string, unschedulable bool) error
+
+	// UpdateNode -> change labels and capacity of node
+	UpdateNode(ctx context.Context, nodeID string, update types.NodeUpdate) error
+
+	// SharedCPUSet -> cores of unpinned containers on node ("" = not restricted)
+	SharedCPUSet(nodeID string) string
 }
 
 // Store interface
@@ -79,6 +96,22 @@ type TaskStore interface {
 	IncrementRestartCounter(ctx context.Context, id string) error
 }
 
+// VolumeStore -> tracked volumes and their node binding
+type VolumeStore interface {
+	// Create new Volume record
+	Create(ctx context.Context, volume *types.Volume) error
+	// Get Volume by name
+	Get(ctx context.Context, name string) (*types.Volume, error)
+	// Update Volume
+	Update(ctx context.Context, volume *types.Volume) error
+	// Delete Volume record
+	Delete(ctx context.Context, name string) error
+	// List all Volumes
+	List(ctx context.Context) ([]*types.Volume, error)
+	// List claim Volumes of service
+	ListByService(ctx context.Context, serviceName string) ([]*types.Volume, error)
+}
+
 // Orchestrator settings
 type OrchestratorSettings struct {
 	ReconcileInterval   time.Duration // reconcile interval
@@ -99,11 +132,12 @@ func DefaultOrchestratorSettings() *OrchestratorSettings {
 
 // Orchestrator struct
 type Orchestrator struct {
-	settings     *OrchestratorSettings
-	appConfig    *types.OchestratorConfig
-	taskStore    TaskStore
-	dockerClient *client.DockerClient
-	scheduler    Scheduler
+	settings    *OrchestratorSettings
+	appConfig   *types.OchestratorConfig
+	taskStore   TaskStore
+	volumeStore VolumeStore
+	runtimes    *client.Pool
+	scheduler   Scheduler
 
 	ctx       context.Context
 	cancel    context.CancelFunc
@@ -112,18 +146,39 @@ type Orchestrator struct {
 	isRunning bool
 	mu        sync.RWMutex
 
-	metricsCollector *metrics.MetricsCollector
-	metricsStore     *metrics.MetricsStore
+	metricsStore *metrics.MetricsStore
 
 	lastScaleTime map[string]time.Time
 	scaleMu       sync.Mutex
+
+	migrating   map[string]int // service -> running migrations
+	migrationMu sync.Mutex
+
+	removingNodes map[string]bool
+	removingMu    sync.Mutex
+
+	drains  map[string]*drainRun // nodeID -> last drain
+	drainMu sync.Mutex
+
+	maintenance   map[string]*nodeMaintenance // nodeID -> maintenance windows
+	maintenanceMu sync.Mutex
+
+	snapshots   map[string]*types.Snapshot // snapshotID -> snapshot
+	busyVolumes map[string]bool            // volume -> snapshot or restore in progress
+	snapshotMu  sync.Mutex
+
+	lastNetworkGC time.Time // reconcile only
+
+	balancer Balancer       // ingress proxy, nil when disabled
+	secrets  *secrets.Store // encrypted secrets, nil -> services with secrets fail to start
 }
 
 // Orch constructor
 func New(
 	appConfig *types.OchestratorConfig,
 	taskStore TaskStore,
-	dockerClient *client.DockerClient,
+	volumeStore VolumeStore,
+	runtimes *client.Pool,
 	scheduler Scheduler,
 	logger *slog.Logger,
 ) *Orchestrator {
@@ -131,19 +186,43 @@ func New(
 		logger = slog.Default()
 	}
 
-	metricsCollector := metrics.NewMetricscollector(dockerClient.GetClient())
-
-	return &Orchestrator{
-		settings:         DefaultOrchestratorSettings(),
-		appConfig:        appConfig,
-		taskStore:        taskStore,
-		dockerClient:     dockerClient,
-		scheduler:        scheduler,
-		logger:           logger.With("component", "orchestrator"),
-		metricsCollector: metricsCollector,
-		metricsStore:     metrics.NewMetricsStore(1000),
-		lastScaleTime:    make(map[string]time.Time),
+	o := &Orchestrator{
+		settings:      DefaultOrchestratorSettings(),
+		appConfig:     appConfig,
+		taskStore:     taskStore,
+		volumeStore:   volumeStore,
+		runtimes:      runtimes,
+		scheduler:     scheduler,
+		logger:        logger.With("component", "orchestrator"),
+		metricsStore:  metrics.NewMetricsStore(1000),
+		lastScaleTime: make(map[string]time.Time),
+		migrating:     make(map[string]int),
+		removingNodes: make(map[string]bool),
+		drains:        make(map[string]*drainRun),
+		maintenance:   make(map[string]*nodeMaintenance),
+		snapshots:     make(map[string]*types.Snapshot),
+		busyVolumes:   make(map[string]bool),
+	}
+
+	// Maintenance windows from config (already validated)
+	for _, nc := range appConfig.Nodes {
+		if windows, err := parseMaintenance(nc.Maintenance); err == nil && len(windows) > 0 {
+			o.maintenance[nc.ID] = &nodeMaintenance{windows: windows}
+		}
 	}
+
+	// Volumes from config are tracked before any task needs them
+	o.declareVolumes(context.Background())
+
+	// Snapshots taken before restart
+	o.loadSnapshots()
+
+	return o
+}
+
+// runtime -> container runtime of node where task lives
+func (o *Orchestrator) runtime(nodeID string) client.ContainerManager {
+	return o.runtimes.Get(nodeID)
 }
 
 // Start Orchestrator
@@ -158,11 +237,19 @@ func (o *Orchestrator) Start() error {
 	o.ctx, o.cancel = context.WithCancel(context.Background())
 	o.isRunning = true
 
-	// Background cycles -> 3 main Loops
-	o.wg.Add(3)
+	// Background cycles -> 3 main Loops + maintenance windows + scheduled backups
+	o.wg.Add(5)
 	go o.healthCheckLoop()
 	go o.reconcileLoop()
 	go o.cleanUpLoop()
+	go o.maintenanceLoop()
+	go o.backupLoop()
+
+	// Optional 4th Loop -> rebalancer
+	if o.appConfig.Rebalancer.Enabled {
+		o.wg.Add(1)
+		go o.rebalanceLoop()
+	}
 
 	// Init services from config
 	if err := o.initServices(); err != nil {
@@ -244,6 +331,10 @@ func (o *Orchestrator) initServices() error {
 		// Make replicas
 		for i := len(existingTasks); i < replicas; i++ {
 			if err := o.createServiceTask(ctx, &svc); err != nil {
+				// Next ordinal starts after previous one is healthy -> reconcile continues
+				if errors.Is(err, errOrdinalNotReady) {
+					break
+				}
 				o.logger.Error("failed to create task during init",
 					"service", svc.ServiceName,
 					"error", err)
@@ -254,36 +345,52 @@ func (o *Orchestrator) initServices() error {
 	return nil
 }
 
-// Collect metrics
-func (o *Orchestrator) collectMetrics(ctx context.Context) {
+// Collect metrics -> memory usage by task ID
+func (o *Orchestrator) collectMetrics(ctx context.Context) map[string]int64 {
 	tasks, err := o.taskStore.ListByStatus(ctx, types.TaskStatusRunning)
 	if err != nil {
 		o.logger.Error("failed to list running tasks for metrics", "error", err)
-		return
+		return nil
 	}
 
 	o.logger.Debug("collectMetrics: found running tasks", "count", len(tasks))
 
 	if len(tasks) == 0 {
 		o.logger.Warn("collectMetrics: no running tasks found - metrics collection skipped")
-		return
+		return nil
 	}
 
+	memoryUsage := make(map[string]int64, len(tasks))
+
 	serviceMetrics := make(map[string]*types.ServiceMetrics)
 	collectedCount := 0
 	failedCount := 0
 
+	collectors := make(map[string]*metrics.MetricsCollector)
+
 	for _, task := range tasks {
 		if task.ContainerID == "" {
 			o.logger.Debug("collectMetrics: task has no ContainerID", "task_id", task.ID)
 			continue
 		}
 
+		collector, exists := collectors[task.NodeID]
+		if !exists {
+			if cli := o.runtime(task.NodeID).GetClient(); cli != nil {
+				collector = metrics.NewMetricscollector(cli)
+			}
+			collectors[task.NodeID] = collector
+		}
+		if collector == nil {
+			failedCount++
+			continue
+		}
+
 		o.logger.Debug("collectMetrics: collecting from container",
 			"task_id", task.ID,
 			"container_id", task.ContainerID[:12])
 
-		metric, err := o.metricsCollector.CollectContainerMetrics(ctx, task.ContainerID)
+		metric, err := collector.CollectContainerMetrics(ctx, task.ContainerID)
 		if err != nil {
 			o.logger.Error("collectMetrics: failed to collect",
 				"task_id", task.ID,
@@ -296,6 +403,7 @@ func (o *Orchestrator) collectMetrics(ctx context.Context) {
 		metric.TaskID = task.ID
 		metric.ServiceName = task.ServiceName
 		o.metricsStore.StoreMetrics(metric)
+		memoryUsage[task.ID] = metric.MemoryUsage
 		collectedCount++
 
 		// Agregation by service
@@ -328,16 +436,47 @@ func (o *Orchestrator) collectMetrics(ctx context.Context) {
 			"avg_mem", fmt.Sprintf("%.2f%%", sm.AvgMemoryPercent),
 			"containers", sm.TotalContainers)
 	}
+
+	return memoryUsage
 }
 
 // Create service Task
 func (o *Orchestrator) createServiceTask(ctx context.Context, service *types.ServiceConfig) error {
+	_, err := o.createServiceTaskOn(ctx, service, nil)
+	return err
+}
+
+// createServiceTaskOn -> create Task on one of the candidate nodes (nil -> all nodes),
+// stateful service gets its next ordinal
+func (o *Orchestrator) createServiceTaskOn(ctx context.Context, service *types.ServiceConfig, candidates []*types.Node) (*types.Task, error) {
+	if service.ServiceType == types.ServiceTypeStateful {
+		ordinal, err := o.nextOrdinal(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		return o.createTask(ctx, service, candidates, &ordinal)
+	}
+	return o.createTask(ctx, service, candidates, nil)
+}
+
+// createTask -> place and start Task, ordinal replica prefers node it ran on before
+func (o *Orchestrator) createTask(ctx context.Context, service *types.ServiceConfig, candidates []*types.Node, ordinal *int) (*types.Task, error) {
 	taskID := uuid.New().String()
 
 	// Choose Node for Task
-	nodes, err := o.scheduler.GetNodes(ctx)
+	nodes := candidates
+	if nodes == nil {
+		var err error
+		nodes, err = o.scheduler.GetNodes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nodes: %w", err)
+		}
+	}
+
+	// Named volumes and their node bindings
+	volumes, err := o.taskVolumes(ctx, service, ordinal)
 	if err != nil {
-		return fmt.Errorf("failed to get nodes: %w", err)
+		return nil, err
 	}
 
 	// Task For Scheduler -> TEMP
@@ -345,11 +484,18 @@ func (o *Orchestrator) createServiceTask(ctx context.Context, service *types.Ser
 		ID:            taskID,
 		ServiceName:   service.ServiceName,
 		ServiceConfig: service,
+		Volumes:       volumes,
 	}
 
-	nodeID, err := o.scheduler.SelectNode(ctx, tempTask, nodes)
-	if err != nil {
-		return fmt.Errorf("failed to select node: %w", err)
+	nodeID := ""
+	if ordinal != nil {
+		nodeID = o.selectOrdinalNode(ctx, tempTask, nodes, *ordinal)
+	}
+	if nodeID == "" {
+		nodeID, err = o.scheduler.SelectNode(ctx, tempTask, nodes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select node: %w", err)
+		}
 	}
 
 	// Make Task
@@ -364,28 +510,40 @@ func (o *Orchestrator) createServiceTask(ctx context.Context, service *types.Ser
 		UpdatedAt:     now,
 		ServiceConfig: service,
 		RestartCount:  0,
-		PortMapping:   service.Ports,
+		PortMapping:   tempTask.PortMapping, // host ports allocated by scheduler
+		CPUSet:        tempTask.CPUSet,      // cores pinned by scheduler
+		Volumes:       volumes,
 		Labels: map[string]string{
 			"service":    service.ServiceName,
 			"created_by": "orchestrator",
 		},
 	}
+	if ordinal != nil {
+		task.Ordinal = ordinal
+		task.Hostname = types.OrdinalHostname(service.ServiceName, *ordinal)
+	}
+	task.Networks = o.taskNetworks(service, task.Hostname)
+	task.Secrets = taskSecrets(service)
 
 	// Save in Store
 	if err := o.taskStore.Create(ctx, task); err != nil {
 		o.scheduler.ReleaseNodeResources(ctx, nodeID, task)
-		return fmt.Errorf("failed to save task: %w", err)
+		return nil, fmt.Errorf("failed to save task: %w", err)
 	}
 
+	// Local volumes stay on this node from now on
+	o.bindVolumes(ctx, task)
+
 	o.logger.Info("task created and saved",
 		"task_id", taskID,
 		"service", service.ServiceName,
-		"node", nodeID)
+		"node", nodeID,
+		"hostname", task.Hostname)
 
 	// Do Task
-	go o.executeTask(task)
+	go o.executeTask(task.DeepCopy())
 
-	return nil
+	return task, nil
 }
 
 // executeTask do Task -> make docker container
@@ -421,11 +579,21 @@ func (o *Orchestrator) executeTask(task *types.Task) {
 		"image", task.ServiceConfig.Image,
 		"node", task.NodeID)
 
-	// 3. Create Container
-	containerID, err := o.dockerClient.CreateContainer(
+	// 3. Create Container, secret values only in copy passed to runtime
+	runTask, err := o.withSecretValues(task)
+	if err != nil {
+		taskLogger.Error("executeTask: failed to resolve secrets", "error", err)
+		task.Status = types.TaskStatusFailed
+		task.Error = err.Error()
+		now := time.Now()
+		task.FinishedAt = &now
+		o.taskStore.Update(ctx, task)
+		o.scheduler.ReleaseNodeResources(ctx, task.NodeID, task)
+		return
+	}
+	containerID, err := o.runtime(task.NodeID).CreateContainer(
 		ctx,
-		task.ServiceConfig,
-		task.ID,
+		runTask,
 		taskLogger,
 	)
 
@@ -449,15 +617,22 @@ func (o *Orchestrator) executeTask(task *types.Task) {
 	task.Status = types.TaskStatusRunning
 	now := time.Now()
 	task.StartedAt = &now
+	task.HealthyAt = nil // new container passes its own health check first
 
 	if err := o.taskStore.Update(ctx, task); err != nil {
 		taskLogger.Error("executeTask: failed to update status to running - rolling back", "error", err)
 		// Rollback: stop and remove container
-		o.dockerClient.StopContainer(ctx, containerID)
-		o.dockerClient.RemoveContainer(ctx, containerID)
+		o.runtime(task.NodeID).StopContainer(ctx, containerID)
+		o.runtime(task.NodeID).RemoveContainer(ctx, containerID)
 		o.scheduler.ReleaseNodeResources(ctx, task.NodeID, task)
 		return
 	}
+	o.notifyBalancer(task.ServiceName)
+
+	// Pinned cores are taken out of shared pool of node right away
+	if isPinned(task) {
+		o.syncNodeSharedCPUs(ctx, task.NodeID)
+	}
 
 	taskLogger.Info("executeTask: container started successfully", "container_id", containerID[:12])
 }
@@ -483,93 +658,33 @@ func (o *Orchestrator) reconcileLoop() {
 
 // reconcile -> check and fix cluster status
 func (o *Orchestrator) reconcile() {
-	ctx := o.ctx // Use orchestrator context
+	// Use orchestrator context, stopped tasks drain connections at most drain_timeout per pass
+	ctx := o.withDrainDeadline(o.ctx)
 	o.logger.Debug("starting reconciliation")
 
-	// 1. Get all Tasks that we have
+	// 1. Collect metrics for all running containers, evict by QoS under memory pressure
+	memoryUsage := o.collectMetrics(ctx)
+	o.relieveMemoryPressure(ctx, memoryUsage)
+
+	// 2. Get all Tasks that we have
 	tasks, err := o.taskStore.List(ctx)
 	if err != nil {
 		o.logger.Error("failed to list tasks", "error", err)
 		return
 	}
 
-	// 2. Group Tasks by service
+	// Tasks of nodes without heartbeat -> lost after grace period, replaced below
+	o.handleLostNodes(ctx, tasks)
+
+	// Unpinned containers follow shared cores after pinned tasks come and go
+	o.syncSharedCPUs(ctx, tasks)
+
+	// 3. Group Tasks by service
 	tasksByService := make(map[string][]*types.Task)
 	for _, task := range tasks {
 		tasksByService[task.ServiceName] = append(tasksByService[task.ServiceName], task)
 	}
 
-	// 3. Collect metrics for all running containers
-	o.collectMetrics(ctx)
-
-	// Drainig nodes...
-	nodes, err := o.scheduler.GetNodes(ctx)
-	if err != nil {
-		o.logger.Error("failed to get Nodes from scheduler -> draining check", "error", err)
-	} else {
-		for _, node := range nodes {
-			node.Mu.RLock()
-			isDraining := node.Status == types.NodeStatusDraining
-			node.Mu.RUnlock()
-
-			if !isDraining {
-				continue
-			}
-
-			drainingTasks, err := o.taskStore.ListByNodeID(ctx, node.ID)
-			if err != nil {
-				o.logger.Error("failed to list tasks on draining node",
-					"node_id", node.ID, "error", err)
-				continue
-			}
-
-			for _, task := range drainingTasks {
-				if task.Status != types.TaskStatusRunning {
-					continue
-				}
-				o.logger.Info("evacuating task from draining node",
-					"task_id", task.ID,
-					"service", task.ServiceName,
-					"node", node.ID,
-				)
-
-				if task.NodeID != "" {
-					o.scheduler.ReleaseNodeResources(ctx, task.NodeID, task)
-				}
-
-				// Stop and remove container
-				if task.ContainerID != "" {
-					o.dockerClient.StopContainer(ctx, task.ContainerID)
-					o.dockerClient.DisconnectFromNetwork(ctx, task.ContainerID)
-					if err := o.dockerClient.RemoveContainer(ctx, task.ContainerID); err != nil {
-						o.logger.Warn("failed to remove container from drained node",
-							"task_id", task.ID,
-							"container", task.ContainerID[:12],
-							"error", err)
-					}
-				}
-
-				// Mark as stopped
-				task.Status = types.TaskStatusStopped
-				now := time.Now()
-				task.FinishedAt = &now
-				task.DesiredState = types.TaskStatusStopped
-				o.taskStore.Update(ctx, task)
-
-				// Recreate on another node
-				for i := range o.appConfig.Services {
-					if o.appConfig.Services[i].ServiceName == task.ServiceName {
-						if err := o.createServiceTask(ctx, &o.appConfig.Services[i]); err != nil {
-							o.logger.Error("failed to recreate task from drained node",
-								"service", task.ServiceName, "error", err)
-						}
-						break
-					}
-				}
-			}
-		}
-	}
-
 	// 4. For each service in config
 	for i := range o.appConfig.Services {
 		svc := &o.appConfig.Services[i]
@@ -632,12 +747,12 @@ func (o *Orchestrator) reconcile() {
 				}
 
 				if task.ContainerID != "" {
-					o.dockerClient.StopContainer(ctx, task.ContainerID)
+					o.runtime(task.NodeID).StopContainer(ctx, task.ContainerID)
 
-					o.dockerClient.DisconnectFromNetwork(ctx, task.ContainerID)
+					o.runtime(task.NodeID).DisconnectFromNetwork(ctx, task.ContainerID)
 
 					// Delete
-					if err := o.dockerClient.RemoveContainer(ctx, task.ContainerID); err != nil {
+					if err := o.runtime(task.NodeID).RemoveContainer(ctx, task.ContainerID); err != nil {
 						o.logger.Warn("failed to remove container",
 							"task_id", task.ID,
 							"container", task.ContainerID[:12],
@@ -645,6 +760,11 @@ func (o *Orchestrator) reconcile() {
 					}
 				}
 
+				// Stateful replica comes back in order with the same ordinal (scale up below)
+				if svc.ServiceType == types.ServiceTypeStateful {
+					continue
+				}
+
 				// Create replacement task
 				o.logger.Info("creating replacement task",
 					"service", svc.ServiceName,
@@ -658,6 +778,13 @@ func (o *Orchestrator) reconcile() {
 			}
 		}
 
+		// Surge task of running migration must not be scaled down
+		if o.isMigrating(svc.ServiceName) {
+			o.logger.Debug("skipping scaling, migration in progress",
+				"service", svc.ServiceName)
+			continue
+		}
+
 		// 6. Calculate desired replicas
 		currentReplicas := running + pending
 		desiredReplicas := o.calculateDesiredReplicas(svc, currentReplicas)
@@ -674,6 +801,12 @@ func (o *Orchestrator) reconcile() {
 
 			for i := 0; i < missing; i++ {
 				if err := o.createServiceTask(ctx, svc); err != nil {
+					if errors.Is(err, errOrdinalNotReady) {
+						o.logger.Debug("stateful scale up waits for previous ordinal",
+							"service", svc.ServiceName,
+							"reason", err)
+						break
+					}
 					o.logger.Error("failed to scale up",
 						"service", svc.ServiceName,
 						"attempt", i+1,
@@ -714,6 +847,12 @@ func (o *Orchestrator) reconcile() {
 	// 9. Cleanup orphaned tasks (not in config anymore)
 	o.cleanupOrphanedTasks(ctx, tasks, tasksByService)
 
+	// 10. Claim volumes of removed services -> retain or delete, unused networks -> removed
+	if refreshed, err := o.taskStore.List(ctx); err == nil {
+		o.reclaimVolumes(ctx, refreshed)
+		o.collectNetworks(ctx, refreshed)
+	}
+
 	o.logger.Debug("reconciliation completed")
 }
 
@@ -773,9 +912,11 @@ func (o *Orchestrator) calculateDesiredReplicas(service *types.ServiceConfig, cu
 
 // scaleDownService -> Scale DOWN
 func (o *Orchestrator) scaleDownService(ctx context.Context, service *types.ServiceConfig, tasks []*types.Task, excess int) {
+	// Stateful -> starting replicas too, ordinals are stopped from the highest one
+	stateful := service.ServiceType == types.ServiceTypeStateful
 	runningTasks := make([]*types.Task, 0)
 	for _, task := range tasks {
-		if task.Status == types.TaskStatusRunning {
+		if task.Status == types.TaskStatusRunning || (stateful && task.IsActive()) {
 			runningTasks = append(runningTasks, task)
 		}
 	}
@@ -795,11 +936,20 @@ func (o *Orchestrator) scaleDownService(ctx context.Context, service *types.Serv
 	for i := 0; i < len(runningTasks) && stopped < excess; i++ {
 		task := runningTasks[i]
 
+		// Highest ordinal still starting -> stop it once it is up, next reconcile
+		if stateful && task.Status != types.TaskStatusRunning {
+			break
+		}
+
 		// Check if orch can stop task
 		if !o.canStopTask(ctx, task, service, tasks, stopped) {
 			o.logger.Debug("skipping task stop due to service constraints",
 				"task_id", task.ID,
 				"service", service.ServiceName)
+			// Lower ordinal must not go before higher one
+			if stateful {
+				break
+			}
 			continue
 		}
 
@@ -811,48 +961,10 @@ func (o *Orchestrator) scaleDownService(ctx context.Context, service *types.Serv
 			"total_to_stop", excess,
 			"service_type", service.ServiceType)
 
-		// Update desired state
-		task.DesiredState = types.TaskStatusStopped
-		if err := o.taskStore.Update(ctx, task); err != nil {
-			o.logger.Error("failed to update task desired state",
-				"task_id", task.ID,
-				"error", err)
+		if err := o.stopTask(ctx, task); err != nil {
 			continue
 		}
 
-		if task.ContainerID != "" {
-			o.dockerClient.StopContainer(ctx, task.ContainerID)
-
-			o.dockerClient.DisconnectFromNetwork(ctx, task.ContainerID)
-
-			if err := o.dockerClient.RemoveContainer(ctx, task.ContainerID); err != nil {
-				o.logger.Warn("failed to remove container",
-					"task_id", task.ID,
-					"container", task.ContainerID[:12],
-					"error", err)
-			}
-		}
-
-		// Release resources
-		if task.NodeID != "" {
-			if err := o.scheduler.ReleaseNodeResources(ctx, task.NodeID, task); err != nil {
-				o.logger.Error("failed to release resources",
-					"task_id", task.ID,
-					"node", task.NodeID,
-					"error", err)
-			}
-		}
-
-		// Update task status
-		task.Status = types.TaskStatusStopped
-		now := time.Now()
-		task.FinishedAt = &now
-		if err := o.taskStore.Update(ctx, task); err != nil {
-			o.logger.Error("failed to update task status to stopped",
-				"task_id", task.ID,
-				"error", err)
-		}
-
 		stopped++
 	}
 
@@ -866,12 +978,67 @@ func (o *Orchestrator) scaleDownService(ctx context.Context, service *types.Serv
 	}
 }
 
+// stopTask -> voluntary stop: container removed, resources released, task marked as stopped
+func (o *Orchestrator) stopTask(ctx context.Context, task *types.Task) error {
+	// Update desired state
+	task.DesiredState = types.TaskStatusStopped
+	if err := o.taskStore.Update(ctx, task); err != nil {
+		o.logger.Error("failed to update task desired state",
+			"task_id", task.ID,
+			"error", err)
+		return err
+	}
+
+	// Ingress stops sending new connections, open ones finish first
+	o.drainBalancer(ctx, task)
+
+	if task.ContainerID != "" {
+		o.runtime(task.NodeID).StopContainer(ctx, task.ContainerID)
+
+		o.runtime(task.NodeID).DisconnectFromNetwork(ctx, task.ContainerID)
+
+		if err := o.runtime(task.NodeID).RemoveContainer(ctx, task.ContainerID); err != nil {
+			o.logger.Warn("failed to remove container",
+				"task_id", task.ID,
+				"container", task.ContainerID[:12],
+				"error", err)
+		}
+	}
+
+	// Release resources
+	if task.NodeID != "" {
+		if err := o.scheduler.ReleaseNodeResources(ctx, task.NodeID, task); err != nil {
+			o.logger.Error("failed to release resources",
+				"task_id", task.ID,
+				"node", task.NodeID,
+				"error", err)
+		}
+	}
+
+	// Update task status
+	task.Status = types.TaskStatusStopped
+	now := time.Now()
+	task.FinishedAt = &now
+	if err := o.taskStore.Update(ctx, task); err != nil {
+		o.logger.Error("failed to update task status to stopped",
+			"task_id", task.ID,
+			"error", err)
+	}
+
+	return nil
+}
+
 // sortTasksForScaleDown -> Sort Tasks for Scale Down
 func (o *Orchestrator) sortTasksForScaleDown(tasks []*types.Task, service *types.ServiceConfig) {
 	// Sort Tasks on most CPU BOUND nodes
 	sort.Slice(tasks, func(i, j int) bool {
 		if service.ServiceType == types.ServiceTypeStateful {
-			return o.isNodePreferredForStateful(o.ctx, tasks[i], service) >
+			// Highest ordinal is stopped first, replicas without ordinal before all
+			if tasks[i].Ordinal != nil || tasks[j].Ordinal != nil {
+				return ordinalOf(tasks[i]) > ordinalOf(tasks[j])
+			}
+			// Least preferred placement is stopped first
+			return o.isNodePreferredForStateful(o.ctx, tasks[i], service) <
 				o.isNodePreferredForStateful(o.ctx, tasks[j], service)
 		}
 
@@ -881,6 +1048,63 @@ func (o *Orchestrator) sortTasksForScaleDown(tasks []*types.Task, service *types
 
 		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
 	})
+
+	if len(service.TopologySpread) > 0 && service.ServiceType != types.ServiceTypeStateful {
+		o.orderTasksBySpread(tasks, service)
+	}
+}
+
+// orderTasksBySpread -> victims are taken one by one from the most populated domains
+func (o *Orchestrator) orderTasksBySpread(tasks []*types.Task, service *types.ServiceConfig) {
+	nodes, err := o.scheduler.GetNodes(o.ctx)
+	if err != nil {
+		return
+	}
+	nodeByID := make(map[string]*types.Node, len(nodes))
+	for _, node := range nodes {
+		nodeByID[node.ID] = node
+	}
+
+	// Task domains and domain counts for every constraint
+	domains := make([][]string, len(tasks))
+	counts := make([]map[string]int, len(service.TopologySpread))
+	for c, constraint := range service.TopologySpread {
+		counts[c] = make(map[string]int)
+		for i, task := range tasks {
+			domain := ""
+			if node, exists := nodeByID[task.NodeID]; exists {
+				domain, _ = node.TopologyValue(constraint.TopologyKey)
+			}
+			domains[i] = append(domains[i], domain)
+			counts[c][domain]++
+		}
+	}
+
+	// Greedy: pick task from the biggest domains, keep previous order on ties
+	ordered := make([]*types.Task, 0, len(tasks))
+	picked := make([]bool, len(tasks))
+	for len(ordered) < len(tasks) {
+		best, bestScore := -1, -1
+		for i := range tasks {
+			if picked[i] {
+				continue
+			}
+			score := 0
+			for c := range service.TopologySpread {
+				score += counts[c][domains[i][c]]
+			}
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		picked[best] = true
+		ordered = append(ordered, tasks[best])
+		for c := range service.TopologySpread {
+			counts[c][domains[best][c]]--
+		}
+	}
+	copy(tasks, ordered)
 }
 
 // canStopTask -> Check if orch can Stop Task
@@ -928,6 +1152,10 @@ func (o *Orchestrator) canStopTask(ctx context.Context, task *types.Task, servic
 		}
 	}
 
+	if o.breaksServiceAffinity(ctx, task, service) {
+		return false
+	}
+
 	runningCount := 0
 	for _, t := range allTasks {
 		if t.Status == types.TaskStatusRunning {
@@ -945,6 +1173,19 @@ func (o *Orchestrator) canStopTask(ctx context.Context, task *types.Task, servic
 		return false
 	}
 
+	// Scale down is voluntary -> disruption budget of service
+	o.migrationMu.Lock()
+	inProgress := o.migrating[service.ServiceName]
+	o.migrationMu.Unlock()
+	healthy := healthyReplicas(allTasks)
+	if allowed := service.DisruptionBudget.Allowed(o.budgetReplicas(ctx, service), healthy) - inProgress; allowed <= 0 {
+		o.logger.Debug("cannot stop task - disruption budget exhausted",
+			"task_id", task.ID,
+			"healthy", healthy,
+			"in_progress", inProgress)
+		return false
+	}
+
 	return true
 }
 
@@ -953,35 +1194,131 @@ func (o *Orchestrator) isNodePreferredForStateful(ctx context.Context, task *typ
 		return 0
 	}
 
-	taskZone := o.getNodeZone(ctx, task.NodeID)
+	labels := o.getNodeLabels(ctx, task.NodeID)
+
+	// Tasks on nodes that break required rules go first, then by preferred weight
+	if !service.SchedulingConstraints.MatchesRequired(labels) {
+		return math.MinInt32
+	}
+	return service.SchedulingConstraints.PreferenceScore(labels)
+}
 
-	for _, rule := range service.SchedulingConstraints.Affinity {
-		if rule.Type == "zone" {
-			for _, preferredZone := range rule.Values {
-				if taskZone == preferredZone {
-					return 1
+// breaksServiceAffinity -> stopping the task leaves co-located dependent services without it
+func (o *Orchestrator) breaksServiceAffinity(ctx context.Context, task *types.Task, service *types.ServiceConfig) bool {
+	for i := range o.appConfig.Services {
+		dependent := &o.appConfig.Services[i]
+		if dependent.ServiceName == service.ServiceName || dependent.SchedulingConstraints == nil {
+			continue
+		}
+
+		for _, rule := range dependent.SchedulingConstraints.ServiceAffinity {
+			if rule.IsPreferred() || rule.Service != service.ServiceName {
+				continue
+			}
+
+			domainTasks, ok := o.getDomainTasks(ctx, task.NodeID, rule.TopologyKey)
+			if !ok {
+				continue
+			}
+
+			sameService, dependents := 0, 0
+			for _, t := range domainTasks {
+				switch {
+				case t.ID == task.ID:
+				case t.ServiceName == service.ServiceName:
+					sameService++
+				case t.ServiceName == dependent.ServiceName:
+					dependents++
 				}
 			}
+
+			if dependents > 0 && sameService == 0 {
+				o.logger.Debug("cannot stop task - dependent service requires co-location",
+					"task_id", task.ID,
+					"dependent", dependent.ServiceName,
+					"topology_key", rule.TopologyKey)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getDomainTasks -> active tasks in the topology domain of the node
+func (o *Orchestrator) getDomainTasks(ctx context.Context, nodeID, topologyKey string) ([]*types.Task, bool) {
+	nodes, err := o.scheduler.GetNodes(ctx)
+	if err != nil {
+		return nil, false
+	}
+
+	var domain string
+	found := false
+	for _, node := range nodes {
+		if node.ID == nodeID {
+			domain, found = node.TopologyValue(topologyKey)
+			break
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	result := make([]*types.Task, 0)
+	for _, node := range nodes {
+		if value, ok := node.TopologyValue(topologyKey); !ok || value != domain {
+			continue
+		}
+		tasks, err := o.taskStore.ListByNodeID(ctx, node.ID)
+		if err != nil {
+			o.logger.Debug("failed to list node tasks", "node_id", node.ID, "error", err)
+			continue
+		}
+		for _, t := range tasks {
+			if t.IsActive() {
+				result = append(result, t)
+			}
 		}
 	}
-	return 0
+	return result, true
+}
+
+// findService -> Get service config by name
+func (o *Orchestrator) findService(serviceName string) *types.ServiceConfig {
+	for i := range o.appConfig.Services {
+		if o.appConfig.Services[i].ServiceName == serviceName {
+			return &o.appConfig.Services[i]
+		}
+	}
+	return nil
 }
 
 // getNodeZone -> Get Node Zone
 func (o *Orchestrator) getNodeZone(ctx context.Context, nodeID string) string {
+	if zone, exists := o.getNodeLabels(ctx, nodeID)["zone"]; exists {
+		return zone
+	}
+	return "unknown"
+}
+
+// getNodeLabels -> Get copy of Node labels
+func (o *Orchestrator) getNodeLabels(ctx context.Context, nodeID string) map[string]string {
 	nodes, err := o.scheduler.GetNodes(ctx)
 	if err != nil {
-		return "unknown"
+		return map[string]string{}
 	}
 
 	for _, node := range nodes {
 		if node.ID == nodeID {
-			if zone, exists := node.Labels["zone"]; exists {
-				return zone
+			node.Mu.RLock()
+			labels := make(map[string]string, len(node.Labels))
+			for k, v := range node.Labels {
+				labels[k] = v
 			}
+			node.Mu.RUnlock()
+			return labels
 		}
 	}
-	return "unknown"
+	return map[string]string{}
 }
 
 // cleanupOrphanedTasks -> removes tasks whose services are no longer in the configuration
@@ -1407,17 +1744,25 @@ func (o *Orchestrator) checkHealth() {
 		return
 	}
 
+	// Writable layer must stay within requested disk
+	o.checkEphemeralStorage(ctx, tasks)
+
 	checkedCount := 0
 	unhealthyCount := 0
 	errorCount := 0
 
 	for _, task := range tasks {
+		// Evicted above
+		if task.Status != types.TaskStatusRunning {
+			continue
+		}
+
 		if task.ServiceConfig == nil || task.ServiceConfig.HealthCheck == nil {
 			o.logger.Debug("checkHealth: task has no health check config", "task_id", task.ID)
 			continue
 		}
 
-		status, err := o.dockerClient.GetConatinerStatus(ctx, task.ContainerID)
+		status, err := o.runtime(task.NodeID).GetConatinerStatus(ctx, task.ContainerID)
 		if err != nil {
 			o.logger.Error("checkHealth: failed to get container status",
 				"task_id", task.ID,
@@ -1440,12 +1785,13 @@ func (o *Orchestrator) checkHealth() {
 			if task.NodeID != "" {
 				o.scheduler.ReleaseNodeResources(ctx, task.NodeID, task)
 			}
+			o.notifyBalancer(task.ServiceName)
 			unhealthyCount++
 			continue
 		}
 
 		// Check Health
-		healthy, err := o.dockerClient.CheckContainerHealth(ctx, task.ContainerID, task.ServiceConfig.HealthCheck)
+		healthy, err := o.runtime(task.NodeID).CheckContainerHealth(ctx, task.ContainerID, task.ServiceConfig.HealthCheck)
 		if err != nil {
 			o.logger.Error("checkHealth: health check error",
 				"task_id", task.ID,
@@ -1456,6 +1802,16 @@ func (o *Orchestrator) checkHealth() {
 
 		checkedCount++
 
+		// Passed check -> task is ready for service discovery
+		if healthy && task.HealthyAt == nil {
+			now := time.Now()
+			task.HealthyAt = &now
+			if err := o.taskStore.Update(ctx, task); err != nil {
+				o.logger.Error("checkHealth: failed to mark task healthy", "task_id", task.ID, "error", err)
+			}
+			o.notifyBalancer(task.ServiceName)
+		}
+
 		if !healthy {
 			o.logger.Warn("checkHealth: container unhealthy",
 				"task_id", task.ID,
@@ -1469,6 +1825,7 @@ func (o *Orchestrator) checkHealth() {
 			if task.NodeID != "" {
 				o.scheduler.ReleaseNodeResources(ctx, task.NodeID, task)
 			}
+			o.notifyBalancer(task.ServiceName)
 			unhealthyCount++
 		}
 	}
@@ -1552,14 +1909,14 @@ func (o *Orchestrator) DeleteTask(ctx context.Context, id string) error {
 
 	// If container is already started -> delete it
 	if task.ContainerID != "" {
-		if err := o.dockerClient.StopContainer(ctx, task.ContainerID); err != nil {
+		if err := o.runtime(task.NodeID).StopContainer(ctx, task.ContainerID); err != nil {
 			o.logger.Warn("failed to stop container during task deletion",
 				"task_id", id,
 				"container", task.ContainerID[:12],
 				"error", err)
 			// Delete Task from TaskStore
 		}
-		if err := o.dockerClient.RemoveContainer(ctx, task.ContainerID); err != nil {
+		if err := o.runtime(task.NodeID).RemoveContainer(ctx, task.ContainerID); err != nil {
 			o.logger.Warn("failed to remove container during task deletion",
 				"task_id", id,
 				"container", task.ContainerID[:12],
@@ -1571,5 +1928,7 @@ func (o *Orchestrator) DeleteTask(ctx context.Context, id string) error {
 		o.scheduler.ReleaseNodeResources(ctx, task.NodeID, task)
 	}
 
-	return o.taskStore.Delete(ctx, id)
+	err = o.taskStore.Delete(ctx, id)
+	o.notifyBalancer(task.ServiceName)
+	return err
 }
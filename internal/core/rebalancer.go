@@ -0,0 +1,572 @@
+// Package core. rebalancer.go -> фоновый ребалансировщик (descheduler).
+// Находит сервисы с нарушением spread / affinity или перекосом по узлам
+// и переносит реплики по одной: сначала новая задача, затем остановка старой.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// RebalanceMove -> planned migration of one task
+type RebalanceMove struct {
+	TaskID      string   `json:"task_id"`
+	ServiceName string   `json:"service_name"`
+	FromNode    string   `json:"from_node"`
+	TargetNodes []string `json:"target_nodes"`
+	Reason      string   `json:"reason"`
+}
+
+// Fourth Loop: rebalanceLoop -> optional, started only if enabled
+func (o *Orchestrator) rebalanceLoop() {
+	defer o.wg.Done()
+	ticker := time.NewTicker(o.appConfig.Rebalancer.Interval)
+	defer ticker.Stop()
+
+	o.logger.Info("rebalance loop started",
+		"interval", o.appConfig.Rebalancer.Interval,
+		"dry_run", o.appConfig.Rebalancer.DryRun)
+
+	for {
+		select {
+		case <-o.ctx.Done():
+			o.logger.Info("rebalance loop stopped")
+			return
+		case <-ticker.C:
+			o.rebalance()
+		}
+	}
+}
+
+// rebalance -> plan moves and execute at most max_moves_per_cycle of them, each within disruption budget
+func (o *Orchestrator) rebalance() {
+	ctx := o.ctx
+
+	moves, err := o.PlanRebalance(ctx)
+	if err != nil {
+		o.logger.Error("failed to plan rebalance", "error", err)
+		return
+	}
+
+	if len(moves) == 0 {
+		o.logger.Debug("rebalance: cluster is balanced")
+		return
+	}
+
+	if o.appConfig.Rebalancer.DryRun {
+		for _, move := range moves {
+			o.logger.Info("rebalance dry-run: planned move",
+				"task_id", move.TaskID,
+				"service", move.ServiceName,
+				"from", move.FromNode,
+				"to", move.TargetNodes,
+				"reason", move.Reason)
+		}
+		return
+	}
+
+	maxMoves := o.appConfig.Rebalancer.MaxMovesPerCycle
+	for i, move := range moves {
+		if i >= maxMoves {
+			o.logger.Debug("rebalance: max moves per cycle reached",
+				"planned", len(moves),
+				"max_moves", maxMoves)
+			break
+		}
+
+		if err := o.migrateTask(ctx, move); err != nil {
+			if errors.Is(err, errDisruptionBudget) {
+				o.logger.Debug("rebalance: move blocked by disruption budget",
+					"task_id", move.TaskID,
+					"service", move.ServiceName)
+				continue
+			}
+			o.logger.Warn("rebalance: migration failed",
+				"task_id", move.TaskID,
+				"service", move.ServiceName,
+				"error", err)
+		}
+	}
+}
+
+// PlanRebalance -> at most one move per service (API Method)
+func (o *Orchestrator) PlanRebalance(ctx context.Context) ([]RebalanceMove, error) {
+	nodes, err := o.scheduler.GetNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	moves := make([]RebalanceMove, 0)
+	for i := range o.appConfig.Services {
+		svc := &o.appConfig.Services[i]
+
+		if svc.ServiceType == types.ServiceTypeDaemon || svc.ServiceType == types.ServiceTypeBatch {
+			continue
+		}
+		if o.isMigrating(svc.ServiceName) {
+			continue
+		}
+
+		tasks, err := o.taskStore.ListByService(ctx, svc.ServiceName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tasks for service %s: %w", svc.ServiceName, err)
+		}
+
+		if move := o.planServiceMove(ctx, svc, tasks, nodes); move != nil {
+			moves = append(moves, *move)
+		}
+	}
+	return moves, nil
+}
+
+// planServiceMove -> find the worst placed task of the service
+func (o *Orchestrator) planServiceMove(ctx context.Context, svc *types.ServiceConfig, tasks []*types.Task, nodes []*types.Node) *RebalanceMove {
+	running := make([]*types.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.Status == types.TaskStatusRunning && t.DesiredState == types.TaskStatusRunning {
+			running = append(running, t)
+		}
+	}
+
+	// Degraded service is not touched
+	if len(running) == 0 || len(running) < svc.Replicas {
+		return nil
+	}
+
+	nodeByID := make(map[string]*types.Node, len(nodes))
+	eligible := make([]*types.Node, 0, len(nodes))
+	for _, node := range nodes {
+		nodeByID[node.ID] = node
+
+		node.Mu.RLock()
+		ok := node.Status == types.NodeStatusReady && svc.SchedulingConstraints.MatchesRequired(node.Labels)
+		node.Mu.RUnlock()
+		if ok {
+			eligible = append(eligible, node)
+		}
+	}
+
+	// Nodes where a new replica fits right now
+	probe := &types.Task{ID: "rebalance-probe", ServiceName: svc.ServiceName, ServiceConfig: svc}
+	candidates, err := o.scheduler.FilterNodes(ctx, probe, nodes)
+	if err != nil || len(candidates) == 0 {
+		return nil
+	}
+
+	newMove := func(task *types.Task, targets []*types.Node, reason string) *RebalanceMove {
+		ids := make([]string, 0, len(targets))
+		for _, node := range targets {
+			if node.ID != task.NodeID {
+				ids = append(ids, node.ID)
+			}
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		// Dependent service must keep its co-located replica
+		if o.breaksServiceAffinity(ctx, task, svc) {
+			return nil
+		}
+		return &RebalanceMove{
+			TaskID:      task.ID,
+			ServiceName: svc.ServiceName,
+			FromNode:    task.NodeID,
+			TargetNodes: ids,
+			Reason:      reason,
+		}
+	}
+
+	// 1. Required affinity violated (labels changed, node came back...)
+	for _, t := range running {
+		node, exists := nodeByID[t.NodeID]
+		if !exists {
+			continue
+		}
+		node.Mu.RLock()
+		matches := svc.SchedulingConstraints.MatchesRequired(node.Labels)
+		node.Mu.RUnlock()
+
+		if !matches {
+			if move := newMove(t, candidates, "required affinity violated"); move != nil {
+				return move
+			}
+		}
+	}
+
+	// 1b. Required service (anti-)affinity violated (peer service moved or scaled)
+	if svc.SchedulingConstraints.HasServiceRules() {
+		for _, t := range running {
+			if reason, violated := o.serviceRuleViolation(ctx, t, svc, len(running)); violated {
+				if move := newMove(t, candidates, reason); move != nil {
+					return move
+				}
+			}
+		}
+	}
+
+	// 2. Topology spread skew
+	for _, constraint := range svc.TopologySpread {
+		counts := make(map[string]int)
+		for _, node := range eligible {
+			if domain, ok := node.TopologyValue(constraint.TopologyKey); ok {
+				counts[domain] += 0
+			}
+		}
+		taskDomain := make(map[string]string, len(running))
+		for _, t := range running {
+			if node, exists := nodeByID[t.NodeID]; exists {
+				if domain, ok := node.TopologyValue(constraint.TopologyKey); ok {
+					counts[domain]++
+					taskDomain[t.ID] = domain
+				}
+			}
+		}
+
+		maxDomain, minDomain := extremeDomains(counts)
+		if counts[maxDomain]-counts[minDomain] <= constraint.MaxSkew {
+			continue
+		}
+
+		targets := make([]*types.Node, 0)
+		for _, node := range candidates {
+			if domain, ok := node.TopologyValue(constraint.TopologyKey); ok && domain == minDomain {
+				targets = append(targets, node)
+			}
+		}
+		for _, t := range running {
+			if taskDomain[t.ID] == maxDomain {
+				if move := newMove(t, targets, fmt.Sprintf("topology spread skew on %s", constraint.TopologyKey)); move != nil {
+					return move
+				}
+			}
+		}
+	}
+
+	preference := func(node *types.Node) int {
+		node.Mu.RLock()
+		defer node.Mu.RUnlock()
+		return svc.SchedulingConstraints.PreferenceScore(node.Labels)
+	}
+
+	// Replicas by eligible node, steps 3 and 4 must agree on it or they undo each other
+	counts := make(map[string]int, len(eligible))
+	for _, node := range eligible {
+		counts[node.ID] = 0
+	}
+	for _, t := range running {
+		if _, ok := counts[t.NodeID]; ok {
+			counts[t.NodeID]++
+		}
+	}
+
+	// skewAfter -> node skew if one replica moves from -> to
+	skewAfter := func(from, to string) int {
+		after := make(map[string]int, len(counts)+1)
+		for id, count := range counts {
+			after[id] = count
+		}
+		if _, ok := after[from]; ok {
+			after[from]--
+		}
+		after[to]++
+		maxNode, minNode := extremeDomains(after)
+		return after[maxNode] - after[minNode]
+	}
+
+	// 3. Preferred affinity: better scored node is available and move keeps node skew within max_node_skew
+	if svc.SchedulingConstraints.HasPreferred() {
+		bestScore, best := 0, make([]*types.Node, 0)
+		for _, node := range candidates {
+			score := preference(node)
+
+			switch {
+			case len(best) == 0 || score > bestScore:
+				bestScore, best = score, []*types.Node{node}
+			case score == bestScore:
+				best = append(best, node)
+			}
+		}
+
+		for _, t := range running {
+			node, exists := nodeByID[t.NodeID]
+			if !exists || preference(node) >= bestScore {
+				continue
+			}
+
+			targets := make([]*types.Node, 0, len(best))
+			for _, target := range best {
+				if skewAfter(t.NodeID, target.ID) <= o.appConfig.Rebalancer.MaxNodeSkew {
+					targets = append(targets, target)
+				}
+			}
+			if move := newMove(t, targets, "preferred affinity"); move != nil {
+				return move
+			}
+		}
+	}
+
+	// 4. Lopsided distribution between nodes, target is scored at least as source (no undoing step 3)
+	maxNode, minNode := extremeDomains(counts)
+	if counts[maxNode]-counts[minNode] > o.appConfig.Rebalancer.MaxNodeSkew {
+		sourceScore := 0
+		if node, exists := nodeByID[maxNode]; exists {
+			sourceScore = preference(node)
+		}
+		targets := make([]*types.Node, 0)
+		for _, node := range candidates {
+			if counts[node.ID] == counts[minNode] && preference(node) >= sourceScore {
+				targets = append(targets, node)
+			}
+		}
+		for _, t := range running {
+			if t.NodeID == maxNode {
+				if move := newMove(t, targets, "lopsided node distribution"); move != nil {
+					return move
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// serviceRuleViolation -> first required service rule the task breaks in its topology domain
+func (o *Orchestrator) serviceRuleViolation(ctx context.Context, task *types.Task, svc *types.ServiceConfig, replicas int) (string, bool) {
+	peers := func(service, topologyKey string) (int, bool) {
+		domainTasks, ok := o.getDomainTasks(ctx, task.NodeID, topologyKey)
+		if !ok {
+			return 0, false
+		}
+		count := 0
+		for _, t := range domainTasks {
+			if t.ID != task.ID && t.ServiceName == service {
+				count++
+			}
+		}
+		return count, true
+	}
+
+	for _, rule := range svc.SchedulingConstraints.ServiceAffinity {
+		if rule.IsPreferred() {
+			continue
+		}
+		// Self-affinity of a single replica is satisfied
+		if rule.Service == svc.ServiceName && replicas == 1 {
+			continue
+		}
+		if count, ok := peers(rule.Service, rule.TopologyKey); !ok || count == 0 {
+			return fmt.Sprintf("service affinity to %s by %s violated", rule.Service, rule.TopologyKey), true
+		}
+	}
+
+	for _, rule := range svc.SchedulingConstraints.ServiceAntiAffinity {
+		if rule.IsPreferred() {
+			continue
+		}
+		if count, ok := peers(rule.Service, rule.TopologyKey); ok && count > 0 {
+			return fmt.Sprintf("service anti-affinity to %s by %s violated", rule.Service, rule.TopologyKey), true
+		}
+	}
+	return "", false
+}
+
+// migrateTask -> surge within disruption budget: start replacement, wait until it is healthy, then stop the old task
+func (o *Orchestrator) migrateTask(ctx context.Context, move RebalanceMove) error {
+	svc := o.findService(move.ServiceName)
+	if svc == nil {
+		return fmt.Errorf("service %s not found in config", move.ServiceName)
+	}
+	if !o.reserveDisruption(ctx, svc) {
+		return fmt.Errorf("service %s: %w", move.ServiceName, errDisruptionBudget)
+	}
+	defer o.setMigrating(move.ServiceName, false)
+	return o.moveTask(ctx, move)
+}
+
+// moveTask -> migrateTask for caller that already marked service as migrating
+func (o *Orchestrator) moveTask(ctx context.Context, move RebalanceMove) error {
+	svc := o.findService(move.ServiceName)
+	if svc == nil {
+		return fmt.Errorf("service %s not found in config", move.ServiceName)
+	}
+
+	nodes, err := o.scheduler.GetNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get nodes: %w", err)
+	}
+	targets := make([]*types.Node, 0, len(move.TargetNodes))
+	for _, node := range nodes {
+		if containsID(move.TargetNodes, node.ID) {
+			targets = append(targets, node)
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("target nodes are gone")
+	}
+
+	o.logger.Info("rebalance: migrating task",
+		"task_id", move.TaskID,
+		"service", move.ServiceName,
+		"from", move.FromNode,
+		"reason", move.Reason)
+
+	if svc.ServiceType == types.ServiceTypeStateful {
+		return o.moveOrdinalTask(ctx, svc, move, targets)
+	}
+
+	replacement, err := o.createServiceTaskOn(ctx, svc, targets)
+	if err != nil {
+		return fmt.Errorf("failed to create replacement: %w", err)
+	}
+
+	if err := o.waitTaskRunning(ctx, replacement.ID, o.appConfig.Rebalancer.ReadyTimeout); err != nil {
+		if current, getErr := o.taskStore.Get(ctx, replacement.ID); getErr == nil && current.DesiredState == types.TaskStatusRunning {
+			o.stopTask(ctx, current)
+		}
+		return fmt.Errorf("replacement %s is not running: %w", replacement.ID, err)
+	}
+
+	old, err := o.taskStore.Get(ctx, move.TaskID)
+	if err != nil {
+		return fmt.Errorf("old task disappeared: %w", err)
+	}
+	if old.Status == types.TaskStatusRunning {
+		if err := o.stopTask(ctx, old); err != nil {
+			return fmt.Errorf("failed to stop old task: %w", err)
+		}
+	}
+
+	o.logger.Info("rebalance: task migrated",
+		"old_task", move.TaskID,
+		"new_task", replacement.ID,
+		"service", move.ServiceName,
+		"to", replacement.NodeID)
+	return nil
+}
+
+// moveOrdinalTask -> stateful replica keeps its identity: stop old task first, then start same ordinal on target
+func (o *Orchestrator) moveOrdinalTask(ctx context.Context, svc *types.ServiceConfig, move RebalanceMove, targets []*types.Node) error {
+	old, err := o.taskStore.Get(ctx, move.TaskID)
+	if err != nil {
+		return fmt.Errorf("old task disappeared: %w", err)
+	}
+	if old.Ordinal == nil {
+		ordinal, err := o.nextOrdinal(ctx, svc)
+		if err != nil {
+			return err
+		}
+		old.Ordinal = &ordinal
+	}
+	ordinal := *old.Ordinal
+
+	// Local volume of replica keeps it on its node -> nothing to stop
+	volumes, err := o.taskVolumes(ctx, svc, &ordinal)
+	if err != nil {
+		return err
+	}
+	for _, v := range volumes {
+		if v.NodeID != "" && !containsID(move.TargetNodes, v.NodeID) {
+			return fmt.Errorf("task is bound to node %s by local volume %s", v.NodeID, v.Name)
+		}
+	}
+
+	if old.IsActive() {
+		if err := o.stopTask(ctx, old); err != nil {
+			return fmt.Errorf("failed to stop old task: %w", err)
+		}
+	}
+
+	// No room on targets -> reconcile brings ordinal back in order
+	replacement, err := o.createTask(ctx, svc, targets, &ordinal)
+	if err != nil {
+		return fmt.Errorf("failed to create replacement: %w", err)
+	}
+	if err := o.waitTaskRunning(ctx, replacement.ID, o.appConfig.Rebalancer.ReadyTimeout); err != nil {
+		return fmt.Errorf("replacement %s is not running: %w", replacement.ID, err)
+	}
+
+	o.logger.Info("rebalance: stateful task migrated",
+		"old_task", move.TaskID,
+		"new_task", replacement.ID,
+		"hostname", replacement.Hostname,
+		"to", replacement.NodeID)
+	return nil
+}
+
+// waitTaskRunning -> poll store until task is running and passes its health check
+func (o *Orchestrator) waitTaskRunning(ctx context.Context, taskID string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		task, err := o.taskStore.Get(ctx, taskID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case o.taskHealthy(ctx, task):
+			return nil
+		case task.IsTerminated():
+			return fmt.Errorf("task is %s: %s", task.Status, task.Error)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for task: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// isMigrating -> migration of the service is in progress (reconcile must not scale it)
+func (o *Orchestrator) isMigrating(serviceName string) bool {
+	o.migrationMu.Lock()
+	defer o.migrationMu.Unlock()
+	return o.migrating[serviceName] > 0
+}
+
+// setMigrating -> count of running migrations of the service
+func (o *Orchestrator) setMigrating(serviceName string, migrating bool) {
+	o.migrationMu.Lock()
+	defer o.migrationMu.Unlock()
+	if migrating {
+		o.migrating[serviceName]++
+		return
+	}
+	if o.migrating[serviceName]--; o.migrating[serviceName] <= 0 {
+		delete(o.migrating, serviceName)
+	}
+}
+
+// ========= HELPERS =========
+
+// extremeDomains -> domains with max and min counts
+func extremeDomains(counts map[string]int) (string, string) {
+	maxDomain, minDomain := "", ""
+	for domain, count := range counts {
+		if maxDomain == "" || count > counts[maxDomain] {
+			maxDomain = domain
+		}
+		if minDomain == "" || count < counts[minDomain] {
+			minDomain = domain
+		}
+	}
+	return maxDomain, minDomain
+}
+
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
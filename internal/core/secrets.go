@@ -0,0 +1,146 @@
+// Package core. secrets.go -> секреты сервисов: ссылки задачи на секреты, расшифровка
+// значений только на время создания контейнера, управление секретами через API.
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/exitae337/gorchester/internal/secrets"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+var (
+	// ErrSecretsUnavailable -> orchestrator runs without secrets store
+	ErrSecretsUnavailable = errors.New("secrets store is not available")
+	// ErrSecretInUse -> secret referenced by service can't be deleted
+	ErrSecretInUse = errors.New("secret is used by service")
+)
+
+// SetSecrets -> set before Start, without store services with secrets can't start
+func (o *Orchestrator) SetSecrets(store *secrets.Store) {
+	o.secrets = store
+}
+
+// taskSecrets -> references of service secrets, values are resolved on start
+func taskSecrets(svc *types.ServiceConfig) []types.TaskSecret {
+	if len(svc.Secrets) == 0 {
+		return nil
+	}
+	result := make([]types.TaskSecret, 0, len(svc.Secrets))
+	for _, ref := range svc.Secrets {
+		result = append(result, types.TaskSecret{Name: ref.Name, Env: ref.Env, Target: ref.Target})
+	}
+	return result
+}
+
+// withSecretValues -> copy of task for runtime with decrypted values, stored task keeps versions only
+func (o *Orchestrator) withSecretValues(task *types.Task) (*types.Task, error) {
+	if len(task.Secrets) == 0 {
+		return task, nil
+	}
+	if o.secrets == nil {
+		return nil, fmt.Errorf("service uses secrets: %w", ErrSecretsUnavailable)
+	}
+
+	runTask := task.DeepCopy()
+	for i := range runTask.Secrets {
+		value, version, err := o.secrets.Value(runTask.Secrets[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		runTask.Secrets[i].Value = value
+		task.Secrets[i].Version = version
+	}
+	return runTask, nil
+}
+
+// ListSecrets -> metadata of secrets with services referencing them
+func (o *Orchestrator) ListSecrets() ([]types.Secret, error) {
+	if o.secrets == nil {
+		return nil, ErrSecretsUnavailable
+	}
+	list := o.secrets.List()
+	for i := range list {
+		list[i].Services = o.secretServices(list[i].Name)
+	}
+	return list, nil
+}
+
+// GetSecret -> metadata of one secret
+func (o *Orchestrator) GetSecret(name string) (types.Secret, error) {
+	if o.secrets == nil {
+		return types.Secret{}, ErrSecretsUnavailable
+	}
+	sec, err := o.secrets.Get(name)
+	if err != nil {
+		return sec, err
+	}
+	sec.Services = o.secretServices(name)
+	return sec, nil
+}
+
+// CreateSecret -> new secret, tasks waiting for it start on next reconcile
+func (o *Orchestrator) CreateSecret(name string, value []byte) (types.Secret, error) {
+	if o.secrets == nil {
+		return types.Secret{}, ErrSecretsUnavailable
+	}
+	sec, err := o.secrets.Create(name, value)
+	if err != nil {
+		return sec, err
+	}
+	sec.Services = o.secretServices(name)
+	o.logger.Info("secret created", "secret", name)
+	return sec, nil
+}
+
+// RotateSecret -> new value for tasks started from now on, running tasks keep old one
+func (o *Orchestrator) RotateSecret(name string, value []byte) (types.Secret, error) {
+	if o.secrets == nil {
+		return types.Secret{}, ErrSecretsUnavailable
+	}
+	sec, err := o.secrets.Rotate(name, value)
+	if err != nil {
+		return sec, err
+	}
+	sec.Services = o.secretServices(name)
+	o.logger.Info("secret rotated", "secret", name, "version", sec.Version, "services", sec.Services)
+	return sec, nil
+}
+
+// DeleteSecret -> only secret no service references
+func (o *Orchestrator) DeleteSecret(name string) error {
+	if o.secrets == nil {
+		return ErrSecretsUnavailable
+	}
+	if services := o.secretServices(name); len(services) > 0 {
+		return fmt.Errorf("%w: %v", ErrSecretInUse, services)
+	}
+	if err := o.secrets.Delete(name); err != nil {
+		return err
+	}
+	o.logger.Info("secret deleted", "secret", name)
+	return nil
+}
+
+// RedactSecrets -> text with secret values hidden (API responses)
+func (o *Orchestrator) RedactSecrets(text string) string {
+	if o.secrets == nil {
+		return text
+	}
+	return o.secrets.Redact(text)
+}
+
+// secretServices -> services referencing secret
+func (o *Orchestrator) secretServices(name string) []string {
+	var services []string
+	for _, svc := range o.appConfig.Services {
+		for _, ref := range svc.Secrets {
+			if ref.Name == name && !slices.Contains(services, svc.ServiceName) {
+				services = append(services, svc.ServiceName)
+			}
+		}
+	}
+	return services
+}
@@ -0,0 +1,530 @@
+// Package core. snapshots.go -> снимки управляемых томов: quiesce сервиса командой в контейнере,
+// tar-архив тома через вспомогательный контейнер в DataDir/backups, восстановление из снимка
+// и резервное копирование по расписанию сервиса с ограничением числа хранимых снимков.
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/cron"
+	"github.com/exitae337/gorchester/internal/types"
+	"github.com/google/uuid"
+)
+
+// how often backup schedules are checked
+const backupInterval = 30 * time.Second
+
+var errSnapshotNotFound = errors.New("snapshot not found")
+
+// backupDir -> archives and metadata: <DataDir>/backups/<volume>/<id>.tar + <id>.json
+func (o *Orchestrator) backupDir() string {
+	return filepath.Join(o.appConfig.DataDir, "backups")
+}
+
+// loadSnapshots -> metadata written before restart, unfinished snapshots are failed
+func (o *Orchestrator) loadSnapshots() {
+	files, err := filepath.Glob(filepath.Join(o.backupDir(), "*", "*.json"))
+	if err != nil {
+		return
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			o.logger.Warn("failed to read snapshot metadata", "file", file, "error", err)
+			continue
+		}
+		var snap types.Snapshot
+		if err := json.Unmarshal(data, &snap); err != nil || snap.ID == "" {
+			o.logger.Warn("invalid snapshot metadata", "file", file, "error", err)
+			continue
+		}
+		if snap.Status == types.SnapshotStatusRunning {
+			snap.Status = types.SnapshotStatusFailed
+			snap.Error = "interrupted by orchestrator restart"
+			os.Remove(snap.Path)
+			o.saveSnapshot(&snap)
+		}
+		o.snapshots[snap.ID] = &snap
+	}
+
+	if len(o.snapshots) > 0 {
+		o.logger.Info("snapshots loaded", "count", len(o.snapshots))
+	}
+}
+
+// SnapshotVolume -> archive volume now (API Method), quiesce runs backup.pre_snapshot of service
+// Returned snapshot is nil when snapshot could not start
+func (o *Orchestrator) SnapshotVolume(ctx context.Context, name string, quiesce bool) (*types.Snapshot, error) {
+	return o.snapshotVolume(ctx, name, quiesce, false)
+}
+
+func (o *Orchestrator) snapshotVolume(ctx context.Context, name string, quiesce, scheduled bool) (*types.Snapshot, error) {
+	v, err := o.volumeStore.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if v.NodeID == "" {
+		return nil, fmt.Errorf("volume %s is not created on any node yet", name)
+	}
+	services := o.volumeServices(v)
+	if len(services) == 0 {
+		return nil, fmt.Errorf("volume %s is not used by any service", name)
+	}
+	svc := services[0]
+
+	if !o.lockVolume(name) {
+		return nil, fmt.Errorf("volume %s is busy with another snapshot or restore", name)
+	}
+	defer o.unlockVolume(name)
+
+	snap := &types.Snapshot{
+		ID:          uuid.New().String(),
+		Volume:      name,
+		ServiceName: svc.ServiceName,
+		NodeID:      v.NodeID,
+		Image:       svc.Image,
+		Status:      types.SnapshotStatusRunning,
+		Scheduled:   scheduled,
+		CreatedAt:   time.Now(),
+	}
+	snap.Path = filepath.Join(o.backupDir(), name, snap.ID+".tar")
+
+	if err := o.saveSnapshot(snap); err != nil {
+		return nil, fmt.Errorf("failed to save snapshot metadata: %w", err)
+	}
+	o.snapshotMu.Lock()
+	o.snapshots[snap.ID] = snap
+	o.snapshotMu.Unlock()
+
+	err = o.writeSnapshot(ctx, snap, svc, quiesce)
+
+	o.snapshotMu.Lock()
+	now := time.Now()
+	snap.CompletedAt = &now
+	if err != nil {
+		snap.Status = types.SnapshotStatusFailed
+		snap.Error = err.Error()
+		os.Remove(snap.Path)
+	} else {
+		snap.Status = types.SnapshotStatusCompleted
+	}
+	result := *snap
+	o.snapshotMu.Unlock()
+
+	if saveErr := o.saveSnapshot(&result); saveErr != nil {
+		o.logger.Error("failed to save snapshot metadata", "snapshot", snap.ID, "error", saveErr)
+	}
+
+	if err != nil {
+		o.logger.Error("volume snapshot failed",
+			"volume", name,
+			"snapshot", snap.ID,
+			"error", err)
+		return &result, err
+	}
+
+	o.logger.Info("volume snapshot completed",
+		"volume", name,
+		"snapshot", result.ID,
+		"node", result.NodeID,
+		"size", result.Size,
+		"quiesced", result.Quiesced,
+		"duration", now.Sub(result.CreatedAt))
+	return &result, nil
+}
+
+// writeSnapshot -> quiesce containers, archive volume into snapshot path, resume containers
+func (o *Orchestrator) writeSnapshot(ctx context.Context, snap *types.Snapshot, svc *types.ServiceConfig, quiesce bool) error {
+	if err := os.MkdirAll(filepath.Dir(snap.Path), 0o750); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if quiesce && svc.Backup != nil && len(svc.Backup.PreSnapshot) > 0 {
+		tasks, err := o.tasksUsingVolume(ctx, snap.Volume)
+		if err != nil {
+			return err
+		}
+		quiesced, err := o.quiesceTasks(ctx, tasks, svc.Backup.PreSnapshot)
+		defer o.resumeTasks(quiesced, svc.Backup.PostSnapshot)
+		if err != nil {
+			return err
+		}
+		o.snapshotMu.Lock()
+		snap.Quiesced = true
+		o.snapshotMu.Unlock()
+	}
+
+	file, err := os.OpenFile(snap.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer file.Close()
+
+	if err := o.runtime(snap.NodeID).ExportVolume(ctx, snap.Volume, snap.Image, file); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat archive: %w", err)
+	}
+	o.snapshotMu.Lock()
+	snap.Size = info.Size()
+	o.snapshotMu.Unlock()
+	return nil
+}
+
+// quiesceTasks -> pre-snapshot command in running containers, returns tasks to resume
+func (o *Orchestrator) quiesceTasks(ctx context.Context, tasks []*types.Task, cmd []string) ([]*types.Task, error) {
+	quiesced := make([]*types.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Status != types.TaskStatusRunning || task.ContainerID == "" {
+			continue
+		}
+		if _, err := o.runtime(task.NodeID).ExecCommand(ctx, task.ContainerID, cmd); err != nil {
+			return quiesced, fmt.Errorf("pre-snapshot command failed in task %s: %w", task.ID, err)
+		}
+		quiesced = append(quiesced, task)
+	}
+	return quiesced, nil
+}
+
+// resumeTasks -> post-snapshot command, runs after failed snapshot too
+func (o *Orchestrator) resumeTasks(tasks []*types.Task, cmd []string) {
+	if len(cmd) == 0 {
+		return
+	}
+	// Request may be cancelled, container must not stay quiesced
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	for _, task := range tasks {
+		if _, err := o.runtime(task.NodeID).ExecCommand(ctx, task.ContainerID, cmd); err != nil {
+			o.logger.Error("post-snapshot command failed",
+				"task_id", task.ID,
+				"service", task.ServiceName,
+				"error", err)
+		}
+	}
+}
+
+// ListSnapshots -> snapshots of volume (all when empty), newest first (API Method)
+func (o *Orchestrator) ListSnapshots(volume string) []*types.Snapshot {
+	o.snapshotMu.Lock()
+	defer o.snapshotMu.Unlock()
+
+	result := make([]*types.Snapshot, 0, len(o.snapshots))
+	for _, snap := range o.snapshots {
+		if volume != "" && snap.Volume != volume {
+			continue
+		}
+		copied := *snap
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result
+}
+
+// GetSnapshot -> snapshot by ID (API Method)
+func (o *Orchestrator) GetSnapshot(id string) (*types.Snapshot, error) {
+	o.snapshotMu.Lock()
+	defer o.snapshotMu.Unlock()
+
+	snap, exists := o.snapshots[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", errSnapshotNotFound, id)
+	}
+	copied := *snap
+	return &copied, nil
+}
+
+// DeleteSnapshot -> remove archive and metadata (API Method)
+func (o *Orchestrator) DeleteSnapshot(id string) error {
+	snap, err := o.GetSnapshot(id)
+	if err != nil {
+		return err
+	}
+	if !o.lockVolume(snap.Volume) {
+		return fmt.Errorf("volume %s is busy with another snapshot or restore", snap.Volume)
+	}
+	defer o.unlockVolume(snap.Volume)
+
+	return o.removeSnapshot(snap)
+}
+
+func (o *Orchestrator) removeSnapshot(snap *types.Snapshot) error {
+	if err := os.Remove(snap.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove archive: %w", err)
+	}
+	if err := os.Remove(metadataPath(snap)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove snapshot metadata: %w", err)
+	}
+
+	o.snapshotMu.Lock()
+	delete(o.snapshots, snap.ID)
+	o.snapshotMu.Unlock()
+
+	o.logger.Info("snapshot deleted", "snapshot", snap.ID, "volume", snap.Volume)
+	return nil
+}
+
+// RestoreSnapshot -> stop tasks mounting volume, recreate volume from archive (API Method)
+// Tasks are stopped without disruption budget: data under them is replaced
+// Scaling of their services waits until restore ends, then tasks start on restored data
+func (o *Orchestrator) RestoreSnapshot(ctx context.Context, id string) error {
+	snap, err := o.GetSnapshot(id)
+	if err != nil {
+		return err
+	}
+	if snap.Status != types.SnapshotStatusCompleted {
+		return fmt.Errorf("snapshot %s is %s, only completed snapshot can be restored", id, snap.Status)
+	}
+
+	v, err := o.volumeStore.Get(ctx, snap.Volume)
+	if err != nil {
+		return fmt.Errorf("volume %s is not tracked anymore: %w", snap.Volume, err)
+	}
+
+	// Volume lost with its node -> back on node of snapshot if it's still in cluster
+	nodeID := v.NodeID
+	if nodeID == "" {
+		if _, err := o.scheduler.GetNode(ctx, snap.NodeID); err != nil {
+			return fmt.Errorf("volume %s is not bound and node %s of snapshot is gone", v.Name, snap.NodeID)
+		}
+		nodeID = snap.NodeID
+	}
+
+	if !o.lockVolume(v.Name) {
+		return fmt.Errorf("volume %s is busy with another snapshot or restore", v.Name)
+	}
+	defer o.unlockVolume(v.Name)
+
+	// Replicas are not recreated on top of volume being restored
+	for _, svc := range o.volumeServices(v) {
+		o.setMigrating(svc.ServiceName, true)
+		defer o.setMigrating(svc.ServiceName, false)
+	}
+
+	tasks, err := o.tasksUsingVolume(ctx, v.Name)
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := o.stopTask(ctx, task); err != nil {
+			return fmt.Errorf("failed to stop task %s using volume: %w", task.ID, err)
+		}
+	}
+	if len(tasks) > 0 {
+		o.logger.Warn("tasks stopped for volume restore",
+			"volume", v.Name,
+			"snapshot", id,
+			"tasks", len(tasks))
+	}
+
+	archive, err := os.Open(snap.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer archive.Close()
+
+	spec := types.TaskVolume{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		DriverOpts: v.DriverOpts,
+		Labels:     v.Labels,
+	}
+	if err := o.runtime(nodeID).ImportVolume(ctx, spec, snap.Image, archive); err != nil {
+		return err
+	}
+
+	if v.NodeID != nodeID || v.Status != types.VolumeStatusBound {
+		v.NodeID = nodeID
+		v.Status = types.VolumeStatusBound
+		if err := o.volumeStore.Update(ctx, v); err != nil {
+			return fmt.Errorf("failed to bind restored volume: %w", err)
+		}
+	}
+
+	o.logger.Info("volume restored from snapshot",
+		"volume", v.Name,
+		"snapshot", id,
+		"node", nodeID,
+		"snapshot_created_at", snap.CreatedAt)
+	return nil
+}
+
+// Sixth Loop: backupLoop -> scheduled snapshots of service volumes
+func (o *Orchestrator) backupLoop() {
+	defer o.wg.Done()
+	ticker := time.NewTicker(backupInterval)
+	defer ticker.Stop()
+
+	o.logger.Info("backup loop started", "interval", backupInterval)
+
+	lastCheck := time.Now()
+	for {
+		select {
+		case <-o.ctx.Done():
+			o.logger.Info("backup loop stopped")
+			return
+		case now := <-ticker.C:
+			o.runScheduledBackups(o.ctx, lastCheck, now)
+			lastCheck = now
+		}
+	}
+}
+
+// runScheduledBackups -> services whose schedule fired in (from, now]
+func (o *Orchestrator) runScheduledBackups(ctx context.Context, from, now time.Time) {
+	for i := range o.appConfig.Services {
+		svc := &o.appConfig.Services[i]
+		if svc.Backup == nil || svc.Backup.Schedule == "" {
+			continue
+		}
+		schedule, err := cron.Parse(svc.Backup.Schedule)
+		if err != nil || schedule.Next(from).After(now) {
+			continue
+		}
+
+		for _, name := range o.serviceVolumeNames(ctx, svc) {
+			if ctx.Err() != nil {
+				return
+			}
+			if _, err := o.snapshotVolume(ctx, name, true, true); err != nil {
+				o.logger.Warn("scheduled backup skipped",
+					"service", svc.ServiceName,
+					"volume", name,
+					"error", err)
+			}
+			o.pruneSnapshots(name, svc.Backup.Retention)
+		}
+	}
+}
+
+// pruneSnapshots -> keep newest retention scheduled snapshots of volume, manual ones stay
+func (o *Orchestrator) pruneSnapshots(volume string, retention int) {
+	if retention <= 0 || !o.lockVolume(volume) {
+		return
+	}
+	defer o.unlockVolume(volume)
+
+	kept := 0
+	for _, snap := range o.ListSnapshots(volume) {
+		if !snap.Scheduled {
+			continue
+		}
+		if kept < retention {
+			kept++
+			continue
+		}
+		if err := o.removeSnapshot(snap); err != nil {
+			o.logger.Warn("failed to prune snapshot", "snapshot", snap.ID, "error", err)
+		}
+	}
+}
+
+// ========= HELPERS =========
+
+// volumeServices -> services mounting volume: owner of claim volume or services with mount
+func (o *Orchestrator) volumeServices(v *types.Volume) []*types.ServiceConfig {
+	if v.ServiceName != "" {
+		if svc := o.findService(v.ServiceName); svc != nil {
+			return []*types.ServiceConfig{svc}
+		}
+		return nil
+	}
+
+	var services []*types.ServiceConfig
+	for i := range o.appConfig.Services {
+		for _, m := range o.appConfig.Services[i].Mounts {
+			if m.Volume == v.Name {
+				services = append(services, &o.appConfig.Services[i])
+				break
+			}
+		}
+	}
+	return services
+}
+
+// serviceVolumeNames -> mounted volumes and claim volumes of service created so far
+func (o *Orchestrator) serviceVolumeNames(ctx context.Context, svc *types.ServiceConfig) []string {
+	names := make([]string, 0, len(svc.Mounts))
+	for _, m := range svc.Mounts {
+		names = append(names, m.Volume)
+	}
+	claims, err := o.volumeStore.ListByService(ctx, svc.ServiceName)
+	if err != nil {
+		return names
+	}
+	for _, v := range claims {
+		names = append(names, v.Name)
+	}
+	return names
+}
+
+// tasksUsingVolume -> active tasks mounting volume
+func (o *Orchestrator) tasksUsingVolume(ctx context.Context, name string) ([]*types.Task, error) {
+	tasks, err := o.taskStore.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	var result []*types.Task
+	for _, task := range tasks {
+		if !task.IsActive() {
+			continue
+		}
+		for _, v := range task.Volumes {
+			if v.Name == name {
+				result = append(result, task)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// lockVolume -> one snapshot or restore of volume at a time
+func (o *Orchestrator) lockVolume(name string) bool {
+	o.snapshotMu.Lock()
+	defer o.snapshotMu.Unlock()
+	if o.busyVolumes[name] {
+		return false
+	}
+	o.busyVolumes[name] = true
+	return true
+}
+
+func (o *Orchestrator) unlockVolume(name string) {
+	o.snapshotMu.Lock()
+	defer o.snapshotMu.Unlock()
+	delete(o.busyVolumes, name)
+}
+
+// saveSnapshot -> metadata next to archive
+func (o *Orchestrator) saveSnapshot(snap *types.Snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(snap.Path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(metadataPath(snap), data, 0o640)
+}
+
+func metadataPath(snap *types.Snapshot) string {
+	return filepath.Join(filepath.Dir(snap.Path), snap.ID+".json")
+}
@@ -0,0 +1,104 @@
+// Package core. stateful.go -> стабильная идентичность реплик stateful-сервисов:
+// порядковый номер (ordinal), постоянное имя хоста "service-N", запуск по порядку
+// (следующая реплика ждёт здоровую предыдущую) и возврат реплики на прежний узел.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// errOrdinalNotReady -> next ordinal waits until previous ones are running and healthy
+var errOrdinalNotReady = errors.New("previous ordinal is not ready")
+
+// nextOrdinal -> lowest free ordinal of stateful service, once every lower one is healthy
+func (o *Orchestrator) nextOrdinal(ctx context.Context, svc *types.ServiceConfig) (int, error) {
+	tasks, err := o.taskStore.ListByService(ctx, svc.ServiceName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list tasks for service %s: %w", svc.ServiceName, err)
+	}
+
+	taken := make(map[int]*types.Task)
+	for _, task := range tasks {
+		if task.Ordinal != nil && task.IsActive() {
+			taken[*task.Ordinal] = task
+		}
+	}
+
+	next := 0
+	for taken[next] != nil {
+		next++
+	}
+
+	for i := 0; i < next; i++ {
+		if !o.taskHealthy(ctx, taken[i]) {
+			return 0, fmt.Errorf("%w: %s waits for %s",
+				errOrdinalNotReady,
+				types.OrdinalHostname(svc.ServiceName, next),
+				types.OrdinalHostname(svc.ServiceName, i))
+		}
+	}
+	return next, nil
+}
+
+// selectOrdinalNode -> node the ordinal ran on last time if it is a candidate and fits, "" otherwise
+func (o *Orchestrator) selectOrdinalNode(ctx context.Context, task *types.Task, candidates []*types.Node, ordinal int) string {
+	tasks, err := o.taskStore.ListByService(ctx, task.ServiceName)
+	if err != nil {
+		return ""
+	}
+
+	var previous *types.Task
+	for _, t := range tasks {
+		if t.Ordinal == nil || *t.Ordinal != ordinal || t.NodeID == "" {
+			continue
+		}
+		if previous == nil || t.CreatedAt.After(previous.CreatedAt) {
+			previous = t
+		}
+	}
+	if previous == nil {
+		return ""
+	}
+
+	for _, node := range candidates {
+		if node.ID != previous.NodeID {
+			continue
+		}
+		nodeID, err := o.scheduler.SelectNode(ctx, task, []*types.Node{node})
+		if err != nil {
+			o.logger.Debug("previous node of ordinal does not fit, placing elsewhere",
+				"service", task.ServiceName,
+				"ordinal", ordinal,
+				"node", node.ID,
+				"reason", err)
+			return ""
+		}
+		return nodeID
+	}
+	return ""
+}
+
+// taskHealthy -> task is running and passes health check of its service
+func (o *Orchestrator) taskHealthy(ctx context.Context, task *types.Task) bool {
+	if task.Status != types.TaskStatusRunning {
+		return false
+	}
+	if task.ServiceConfig == nil || task.ServiceConfig.HealthCheck == nil {
+		return true
+	}
+	healthy, err := o.runtime(task.NodeID).CheckContainerHealth(ctx, task.ContainerID, task.ServiceConfig.HealthCheck)
+	return err == nil && healthy
+}
+
+// ordinalOf -> ordinal of task, replicas without one sort as highest
+func ordinalOf(task *types.Task) int {
+	if task.Ordinal == nil {
+		return math.MaxInt
+	}
+	return *task.Ordinal
+}
@@ -0,0 +1,235 @@
+// Package core. volumes.go -> управляемые тома: объявленные в конфиге тома и тома реплик
+// stateful-сервисов (volume_claims), привязка локального тома к узлу первой задачи,
+// политика retain / delete после удаления сервиса.
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// declareVolumes -> record for every volume from config (binding of known ones is kept)
+func (o *Orchestrator) declareVolumes(ctx context.Context) {
+	for _, vc := range o.appConfig.Volumes {
+		if _, err := o.volumeStore.Get(ctx, vc.Name); err == nil {
+			continue
+		}
+		err := o.volumeStore.Create(ctx, &types.Volume{
+			Name:       vc.Name,
+			Driver:     vc.Driver,
+			DriverOpts: vc.DriverOpts,
+			Labels:     vc.Labels,
+			Status:     types.VolumeStatusPending,
+		})
+		if err != nil {
+			o.logger.Error("failed to track volume", "volume", vc.Name, "error", err)
+		}
+	}
+}
+
+// taskVolumes -> mounts of service plus claims of ordinal, claim volumes are created on first use
+func (o *Orchestrator) taskVolumes(ctx context.Context, svc *types.ServiceConfig, ordinal *int) ([]types.TaskVolume, error) {
+	if len(svc.Mounts) == 0 && (ordinal == nil || len(svc.VolumeClaims) == 0) {
+		return nil, nil
+	}
+
+	volumes := make([]types.TaskVolume, 0, len(svc.Mounts)+len(svc.VolumeClaims))
+	for _, m := range svc.Mounts {
+		record, err := o.volumeStore.Get(ctx, m.Volume)
+		if err != nil {
+			return nil, fmt.Errorf("volume %s of service %s: %w", m.Volume, svc.ServiceName, err)
+		}
+		volumes = append(volumes, taskVolume(record, m.Target, m.ReadOnly))
+	}
+
+	if ordinal == nil {
+		return volumes, nil
+	}
+
+	for _, claim := range svc.VolumeClaims {
+		name := types.ClaimVolumeName(claim.Name, svc.ServiceName, *ordinal)
+		record, err := o.volumeStore.Get(ctx, name)
+		if err != nil {
+			n := *ordinal
+			record = &types.Volume{
+				Name:          name,
+				Driver:        claim.Driver,
+				DriverOpts:    claim.DriverOpts,
+				Status:        types.VolumeStatusPending,
+				ServiceName:   svc.ServiceName,
+				Claim:         claim.Name,
+				Ordinal:       &n,
+				ReclaimPolicy: svc.VolumeReclaimPolicy,
+			}
+			if err := o.volumeStore.Create(ctx, record); err != nil {
+				return nil, fmt.Errorf("failed to track volume %s: %w", name, err)
+			}
+			o.logger.Info("volume claimed",
+				"volume", name,
+				"service", svc.ServiceName,
+				"ordinal", n)
+		}
+		volumes = append(volumes, taskVolume(record, claim.Target, claim.ReadOnly))
+	}
+	return volumes, nil
+}
+
+// taskVolume -> mount of tracked volume, local one pins task to its node
+func taskVolume(record *types.Volume, target string, readOnly bool) types.TaskVolume {
+	v := types.TaskVolume{
+		Name:       record.Name,
+		Target:     target,
+		ReadOnly:   readOnly,
+		Driver:     record.Driver,
+		DriverOpts: record.DriverOpts,
+		Labels:     record.Labels,
+	}
+	if record.NodeBound() && record.NodeID != "" {
+		v.NodeID = record.NodeID
+	}
+	return v
+}
+
+// bindVolumes -> volumes of placed task are bound to its node
+func (o *Orchestrator) bindVolumes(ctx context.Context, task *types.Task) {
+	for _, v := range task.Volumes {
+		record, err := o.volumeStore.Get(ctx, v.Name)
+		if err != nil {
+			continue
+		}
+		if record.Status == types.VolumeStatusBound && (record.NodeBound() || record.NodeID == task.NodeID) {
+			continue
+		}
+
+		record.Status = types.VolumeStatusBound
+		record.NodeID = task.NodeID
+		if err := o.volumeStore.Update(ctx, record); err != nil {
+			o.logger.Error("failed to bind volume", "volume", v.Name, "error", err)
+			continue
+		}
+		o.logger.Info("volume bound to node",
+			"volume", v.Name,
+			"node", task.NodeID,
+			"task_id", task.ID)
+	}
+}
+
+// reclaimVolumes -> claim volumes of services removed from config: retain or delete
+func (o *Orchestrator) reclaimVolumes(ctx context.Context, tasks []*types.Task) {
+	volumes, err := o.volumeStore.List(ctx)
+	if err != nil {
+		o.logger.Error("failed to list volumes", "error", err)
+		return
+	}
+
+	inUse := volumesInUse(tasks)
+	for _, v := range volumes {
+		if v.ServiceName == "" || v.Status == types.VolumeStatusReleased ||
+			o.findService(v.ServiceName) != nil || inUse[v.Name] {
+			continue
+		}
+
+		if v.ReclaimPolicy == types.VolumeReclaimDelete {
+			if err := o.removeVolume(ctx, v); err != nil {
+				o.logger.Warn("failed to delete volume of removed service, retrying later",
+					"volume", v.Name,
+					"service", v.ServiceName,
+					"error", err)
+			}
+			continue
+		}
+
+		v.Status = types.VolumeStatusReleased
+		if err := o.volumeStore.Update(ctx, v); err != nil {
+			o.logger.Error("failed to release volume", "volume", v.Name, "error", err)
+			continue
+		}
+		o.logger.Info("service removed, volume retained",
+			"volume", v.Name,
+			"service", v.ServiceName,
+			"node", v.NodeID)
+	}
+}
+
+// releaseNodeVolumes -> node left cluster, its local volumes are gone with it
+func (o *Orchestrator) releaseNodeVolumes(ctx context.Context, nodeID string) {
+	volumes, err := o.volumeStore.List(ctx)
+	if err != nil {
+		return
+	}
+	for _, v := range volumes {
+		if v.NodeID != nodeID || !v.NodeBound() {
+			continue
+		}
+		v.NodeID = ""
+		v.Status = types.VolumeStatusPending
+		if err := o.volumeStore.Update(ctx, v); err != nil {
+			continue
+		}
+		o.logger.Warn("node removed, its local volume starts empty on next node",
+			"volume", v.Name,
+			"node", nodeID)
+	}
+}
+
+// ListVolumes -> tracked volumes (API Method)
+func (o *Orchestrator) ListVolumes(ctx context.Context) ([]*types.Volume, error) {
+	return o.volumeStore.List(ctx)
+}
+
+// DeleteVolume -> remove unused volume with its data (API Method), declared one starts over as pending
+func (o *Orchestrator) DeleteVolume(ctx context.Context, name string) error {
+	v, err := o.volumeStore.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := o.taskStore.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+	if volumesInUse(tasks)[name] {
+		return fmt.Errorf("volume %s is used by active task", name)
+	}
+
+	return o.removeVolume(ctx, v)
+}
+
+// removeVolume -> delete volume on its node and forget it (or reset declared one)
+func (o *Orchestrator) removeVolume(ctx context.Context, v *types.Volume) error {
+	if v.NodeID != "" {
+		if err := o.runtime(v.NodeID).RemoveVolume(ctx, v.Name); err != nil {
+			return err
+		}
+	}
+
+	for _, vc := range o.appConfig.Volumes {
+		if vc.Name == v.Name {
+			v.NodeID = ""
+			v.Status = types.VolumeStatusPending
+			return o.volumeStore.Update(ctx, v)
+		}
+	}
+
+	if err := o.volumeStore.Delete(ctx, v.Name); err != nil {
+		return err
+	}
+	o.logger.Info("volume deleted", "volume", v.Name, "node", v.NodeID)
+	return nil
+}
+
+// volumesInUse -> volumes mounted by active tasks
+func volumesInUse(tasks []*types.Task) map[string]bool {
+	inUse := make(map[string]bool)
+	for _, task := range tasks {
+		if !task.IsActive() {
+			continue
+		}
+		for _, v := range task.Volumes {
+			inUse[v.Name] = true
+		}
+	}
+	return inUse
+}
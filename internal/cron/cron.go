@@ -0,0 +1,201 @@
+// Package cron. cron.go -> разбор cron-расписаний (минута, час, день месяца, месяц, день недели)
+// и поиск ближайшего срабатывания. Используется окнами обслуживания узлов.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field -> allowed values of one schedule position
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// descriptors -> shortcuts for common schedules
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// searchLimit -> schedule without match in this period never fires (e.g. 30 feb)
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Schedule -> parsed cron expression, bit i is set when value i matches
+type Schedule struct {
+	spec string
+
+	minute, hour, dom, month, dow uint64
+
+	// "*" in day fields -> other day field alone decides (standard cron rule)
+	domAny, dowAny bool
+}
+
+// Parse -> "30 2 * * sun", "0 */6 * * 1-5", "@weekly"
+func Parse(spec string) (*Schedule, error) {
+	expr := strings.TrimSpace(spec)
+	if descriptor, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields (minute hour day month weekday), got %d", spec, len(parts))
+	}
+
+	s := &Schedule{spec: spec}
+	var err error
+	if s.minute, err = parseField(parts[0], minuteField); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+	if s.hour, err = parseField(parts[1], hourField); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+	if s.dom, err = parseField(parts[2], domField); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+	if s.month, err = parseField(parts[3], monthField); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+	if s.dow, err = parseField(parts[4], dowField); err != nil {
+		return nil, fmt.Errorf("cron %q: %w", spec, err)
+	}
+
+	// Sunday is 0 and 7
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domAny = strings.HasPrefix(parts[2], "*")
+	s.dowAny = strings.HasPrefix(parts[4], "*")
+
+	return s, nil
+}
+
+// parseField -> "*", "5", "1-5", "*/15", "10-40/10", "mon,wed,fri"
+func parseField(expr string, f field) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepExpr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step %q", f.name, stepExpr)
+			}
+			step = n
+		}
+
+		var low, high int
+		switch {
+		case rangeExpr == "*":
+			low, high = f.min, f.max
+		case strings.Contains(rangeExpr, "-"):
+			lowExpr, highExpr, _ := strings.Cut(rangeExpr, "-")
+			var err error
+			if low, err = f.value(lowExpr); err != nil {
+				return 0, err
+			}
+			if high, err = f.value(highExpr); err != nil {
+				return 0, err
+			}
+			if low > high {
+				return 0, fmt.Errorf("%s: range %q is reversed", f.name, rangeExpr)
+			}
+		default:
+			value, err := f.value(rangeExpr)
+			if err != nil {
+				return 0, err
+			}
+			// "5/10" -> from 5 to the end with step
+			low, high = value, value
+			if hasStep {
+				high = f.max
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// value -> number or name within field bounds
+func (f field) value(expr string) (int, error) {
+	if n, ok := f.names[strings.ToLower(expr)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid value %q", f.name, expr)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s: %d is out of range %d-%d", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
+
+// Next -> first time after t that matches schedule (minute precision), zero if never
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches -> both day fields restricted -> either one matches
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// String -> expression as written
+func (s *Schedule) String() string {
+	return s.spec
+}
@@ -0,0 +1,175 @@
+// Package dns. Встроенный DNS-сервер обнаружения сервисов.
+// message.go -> минимальный разбор запроса и сборка ответа (RFC 1035, SRV по RFC 2782).
+package dns
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+)
+
+// Record types and classes
+const (
+	typeA    uint16 = 1
+	typeAAAA uint16 = 28
+	typeSRV  uint16 = 33
+	typeANY  uint16 = 255
+
+	classINET uint16 = 1
+)
+
+// Response codes
+const (
+	rcodeSuccess        = 0
+	rcodeFormatError    = 1
+	rcodeServerFailure  = 2
+	rcodeNameError      = 3 // NXDOMAIN
+	rcodeNotImplemented = 4
+	rcodeRefused        = 5
+)
+
+const (
+	headerLen = 12
+	maxUDPLen = 512 // without EDNS
+)
+
+var errMalformed = errors.New("malformed dns message")
+
+// question -> single question of query
+type question struct {
+	name  string // lower case, without trailing dot
+	qtype uint16
+	class uint16
+	raw   []byte // name in wire format, copied into answer
+}
+
+// record -> resource record of answer
+type record struct {
+	name  []byte // wire format name, nil -> pointer to question name
+	rtype uint16
+	ttl   uint32
+	data  []byte
+}
+
+// parseQuery -> id, flags and first question of query
+func parseQuery(msg []byte) (uint16, uint16, question, error) {
+	if len(msg) < headerLen {
+		return 0, 0, question{}, errMalformed
+	}
+	id := binary.BigEndian.Uint16(msg[0:2])
+	flags := binary.BigEndian.Uint16(msg[2:4])
+	if binary.BigEndian.Uint16(msg[4:6]) != 1 {
+		return id, flags, question{}, errMalformed
+	}
+
+	labels := make([]string, 0, 6)
+	off := headerLen
+	for {
+		if off >= len(msg) {
+			return id, flags, question{}, errMalformed
+		}
+		n := int(msg[off])
+		if n == 0 {
+			off++
+			break
+		}
+		// Compression in question of query is not expected
+		if n&0xC0 != 0 || off+1+n > len(msg) {
+			return id, flags, question{}, errMalformed
+		}
+		labels = append(labels, strings.ToLower(string(msg[off+1:off+1+n])))
+		off += 1 + n
+	}
+	if off+4 > len(msg) {
+		return id, flags, question{}, errMalformed
+	}
+
+	q := question{
+		name:  strings.Join(labels, "."),
+		qtype: binary.BigEndian.Uint16(msg[off : off+2]),
+		class: binary.BigEndian.Uint16(msg[off+2 : off+4]),
+		raw:   append([]byte(nil), msg[headerLen:off]...),
+	}
+	return id, flags, q, nil
+}
+
+// buildResponse -> authoritative answer, truncated to header and question when over limit
+func buildResponse(id, queryFlags uint16, q *question, rcode int, answers, extra []record, limit int) []byte {
+	flags := uint16(1<<15) | queryFlags&0x7800 | 1<<10 | queryFlags&0x0100 | uint16(rcode) // QR, opcode, AA, RD
+
+	msg := make([]byte, headerLen, maxUDPLen)
+	binary.BigEndian.PutUint16(msg[0:2], id)
+	if q == nil {
+		binary.BigEndian.PutUint16(msg[2:4], flags)
+		return msg
+	}
+	binary.BigEndian.PutUint16(msg[4:6], 1)
+	msg = append(msg, q.raw...)
+	msg = binary.BigEndian.AppendUint16(msg, q.qtype)
+	msg = binary.BigEndian.AppendUint16(msg, q.class)
+	questionEnd := len(msg)
+
+	for _, rr := range answers {
+		msg = appendRecord(msg, rr)
+	}
+	for _, rr := range extra {
+		msg = appendRecord(msg, rr)
+	}
+
+	ancount, arcount := len(answers), len(extra)
+	if limit > 0 && len(msg) > limit {
+		// Client retries over TCP
+		msg = msg[:questionEnd]
+		ancount, arcount = 0, 0
+		flags |= 1 << 9 // TC
+	}
+	binary.BigEndian.PutUint16(msg[2:4], flags)
+	binary.BigEndian.PutUint16(msg[6:8], uint16(ancount))
+	binary.BigEndian.PutUint16(msg[10:12], uint16(arcount))
+	return msg
+}
+
+func appendRecord(msg []byte, rr record) []byte {
+	if rr.name == nil {
+		msg = append(msg, 0xC0, headerLen) // pointer to question name
+	} else {
+		msg = append(msg, rr.name...)
+	}
+	msg = binary.BigEndian.AppendUint16(msg, rr.rtype)
+	msg = binary.BigEndian.AppendUint16(msg, classINET)
+	msg = binary.BigEndian.AppendUint32(msg, rr.ttl)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(len(rr.data)))
+	return append(msg, rr.data...)
+}
+
+// encodeName -> wire format of dotted name
+func encodeName(name string) []byte {
+	buf := make([]byte, 0, len(name)+2)
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if label == "" {
+			continue
+		}
+		buf = append(buf, byte(len(label)))
+		buf = append(buf, label...)
+	}
+	return append(buf, 0)
+}
+
+// aRecord -> IPv4 address record, nil for IPv6 address
+func aRecord(name []byte, ip net.IP, ttl uint32) *record {
+	v4 := ip.To4()
+	if v4 == nil {
+		return nil
+	}
+	return &record{name: name, rtype: typeA, ttl: ttl, data: append([]byte(nil), v4...)}
+}
+
+// srvRecord -> priority and weight are equal for all replicas
+func srvRecord(name []byte, port uint16, target string, ttl uint32) record {
+	data := make([]byte, 6, 6+len(target)+2)
+	binary.BigEndian.PutUint16(data[0:2], 10) // priority
+	binary.BigEndian.PutUint16(data[2:4], 10) // weight
+	binary.BigEndian.PutUint16(data[4:6], port)
+	return record{name: name, rtype: typeSRV, ttl: ttl, data: append(data, encodeName(target)...)}
+}
@@ -0,0 +1,335 @@
+// Package dns. server.go -> UDP/TCP сервер зоны <cluster_name>.<domain>:
+// A и SRV записи готовых задач сервиса из источника (оркестратора), без кэша.
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// Port -> published port of replica
+type Port struct {
+	HostPort      int    // Port on node, answered in SRV
+	ContainerPort int    // Port in container, used in _<port>._<proto> names
+	Protocol      string // "tcp" / "udp"
+}
+
+// Endpoint -> ready replica of service
+type Endpoint struct {
+	Name  string // Hostname of stateful replica or short task ID
+	IP    net.IP // Node address
+	Ports []Port
+}
+
+// Source -> where ready replicas come from
+type Source interface {
+	// Endpoints -> ready replicas of service, false when service is unknown
+	Endpoints(ctx context.Context, service string) ([]Endpoint, bool)
+}
+
+// Server -> authoritative server for zone of cluster
+type Server struct {
+	zone     string // "<cluster_name>.<domain>"
+	ttl      uint32
+	upstream []string
+	source   Source
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	udp      net.PacketConn
+	tcp      net.Listener
+	closed   bool
+	handlers sync.WaitGroup
+}
+
+// NewServer -> server for <cluster_name>.<domain> answering from source
+func NewServer(cfg types.DNSConfig, clusterName string, source Source, logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Server{
+		zone:     strings.ToLower(clusterName + "." + strings.Trim(cfg.Domain, ".")),
+		ttl:      uint32(cfg.TTL / time.Second),
+		upstream: cfg.Upstream,
+		source:   source,
+		logger:   logger.With("component", "dns"),
+	}
+}
+
+// Zone -> names are <service>.<zone>
+func (s *Server) Zone() string {
+	return s.zone
+}
+
+// ListenAndServe -> serve UDP and TCP on addr until Close
+func (s *Server) ListenAndServe(addr string) error {
+	udp, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return fmt.Errorf("dns: failed to listen udp %s: %w", addr, err)
+	}
+	tcp, err := net.Listen("tcp", addr)
+	if err != nil {
+		udp.Close()
+		return fmt.Errorf("dns: failed to listen tcp %s: %w", addr, err)
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		udp.Close()
+		tcp.Close()
+		return nil
+	}
+	s.udp, s.tcp = udp, tcp
+	s.mu.Unlock()
+
+	s.logger.Info("dns server started", "addr", addr, "zone", s.zone)
+
+	errs := make(chan error, 2)
+	go func() { errs <- s.serveUDP(udp) }()
+	go func() { errs <- s.serveTCP(tcp) }()
+
+	err = <-errs
+	s.Close()
+	<-errs
+	s.handlers.Wait()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// Close -> stop listeners, queries in progress are finished
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.udp != nil {
+		s.udp.Close()
+	}
+	if s.tcp != nil {
+		s.tcp.Close()
+	}
+	return nil
+}
+
+func (s *Server) serveUDP(conn net.PacketConn) error {
+	buf := make([]byte, 4096)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+		query := append([]byte(nil), buf[:n]...)
+
+		s.handlers.Add(1)
+		go func() {
+			defer s.handlers.Done()
+			if resp := s.handle(query, maxUDPLen); resp != nil {
+				conn.WriteTo(resp, addr)
+			}
+		}()
+	}
+}
+
+func (s *Server) serveTCP(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		s.handlers.Add(1)
+		go func() {
+			defer s.handlers.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// serveConn -> length prefixed messages until client closes or goes idle
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	for {
+		conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+
+		resp := s.handle(query, 0)
+		if resp == nil {
+			return
+		}
+		out := binary.BigEndian.AppendUint16(make([]byte, 0, len(resp)+2), uint16(len(resp)))
+		if _, err := conn.Write(append(out, resp...)); err != nil {
+			return
+		}
+	}
+}
+
+// handle -> response to query, nil when query can't be answered at all
+func (s *Server) handle(query []byte, limit int) []byte {
+	id, flags, q, err := parseQuery(query)
+	if err != nil {
+		if len(query) < headerLen || flags&(1<<15) != 0 {
+			return nil // too short or a response
+		}
+		return buildResponse(id, flags, nil, rcodeFormatError, nil, nil, limit)
+	}
+	if flags&(1<<15) != 0 {
+		return nil
+	}
+	if opcode := (flags >> 11) & 0xF; opcode != 0 {
+		return buildResponse(id, flags, &q, rcodeNotImplemented, nil, nil, limit)
+	}
+
+	if !s.inZone(q.name) && len(s.upstream) > 0 {
+		if resp := s.forward(query); resp != nil {
+			return resp
+		}
+		return buildResponse(id, flags, &q, rcodeServerFailure, nil, nil, limit)
+	}
+
+	rcode, answers, extra := s.answer(&q)
+	return buildResponse(id, flags, &q, rcode, answers, extra, limit)
+}
+
+// inZone -> name is zone apex or below it
+func (s *Server) inZone(name string) bool {
+	return name == s.zone || strings.HasSuffix(name, "."+s.zone)
+}
+
+// forward -> query relayed to first upstream that answers (containers keep outside names)
+func (s *Server) forward(query []byte) []byte {
+	buf := make([]byte, 65535)
+	for _, addr := range s.upstream {
+		conn, err := net.DialTimeout("udp", addr, 2*time.Second)
+		if err != nil {
+			continue
+		}
+		conn.SetDeadline(time.Now().Add(2 * time.Second))
+		if _, err := conn.Write(query); err != nil {
+			conn.Close()
+			continue
+		}
+		n, err := conn.Read(buf)
+		conn.Close()
+		if err != nil || n < headerLen || !bytes.Equal(buf[:2], query[:2]) {
+			s.logger.Debug("upstream did not answer", "upstream", addr, "error", err)
+			continue
+		}
+		return append([]byte(nil), buf[:n]...)
+	}
+	return nil
+}
+
+// answer -> records for name in zone:
+//
+//	<service>.<zone>                  A of all replicas, SRV of all published ports
+//	<replica>.<service>.<zone>        A and SRV of one replica
+//	_<port>._<proto>.<service>.<zone> SRV of one container port
+func (s *Server) answer(q *question) (int, []record, []record) {
+	if q.class != classINET {
+		return rcodeRefused, nil, nil
+	}
+	if !s.inZone(q.name) {
+		return rcodeRefused, nil, nil // not authoritative, no upstream
+	}
+	if q.name == s.zone {
+		return rcodeSuccess, nil, nil
+	}
+
+	labels := strings.Split(strings.TrimSuffix(q.name, "."+s.zone), ".")
+	service := labels[len(labels)-1]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	endpoints, known := s.source.Endpoints(ctx, service)
+	if !known {
+		return rcodeNameError, nil, nil
+	}
+
+	// Narrow down to replica or port
+	containerPort, protocol := 0, ""
+	switch len(labels) {
+	case 1:
+	case 2:
+		name := labels[0]
+		var found []Endpoint
+		for _, ep := range endpoints {
+			if strings.EqualFold(ep.Name, name) {
+				found = append(found, ep)
+			}
+		}
+		if len(found) == 0 {
+			return rcodeNameError, nil, nil
+		}
+		endpoints = found
+	case 3:
+		port, err := strconv.Atoi(strings.TrimPrefix(labels[0], "_"))
+		if err != nil || !strings.HasPrefix(labels[0], "_") || !strings.HasPrefix(labels[1], "_") {
+			return rcodeNameError, nil, nil
+		}
+		containerPort, protocol = port, strings.TrimPrefix(labels[1], "_")
+		if q.qtype != typeSRV && q.qtype != typeANY {
+			return rcodeSuccess, nil, nil
+		}
+	default:
+		return rcodeNameError, nil, nil
+	}
+
+	var answers, extra []record
+	if (q.qtype == typeA || q.qtype == typeANY) && containerPort == 0 {
+		seen := make(map[string]bool)
+		for _, ep := range endpoints {
+			if seen[ep.IP.String()] {
+				continue // replicas on one node share its address
+			}
+			seen[ep.IP.String()] = true
+			if rr := aRecord(nil, ep.IP, s.ttl); rr != nil {
+				answers = append(answers, *rr)
+			}
+		}
+	}
+
+	if q.qtype == typeSRV || q.qtype == typeANY {
+		for _, ep := range endpoints {
+			target := ep.Name + "." + service + "." + s.zone
+			added := false
+			for _, p := range ep.Ports {
+				if p.HostPort <= 0 {
+					continue
+				}
+				if containerPort != 0 && (p.ContainerPort != containerPort || !strings.EqualFold(p.Protocol, protocol)) {
+					continue
+				}
+				answers = append(answers, srvRecord(nil, uint16(p.HostPort), target, s.ttl))
+				added = true
+			}
+			if rr := aRecord(encodeName(target), ep.IP, s.ttl); added && rr != nil {
+				extra = append(extra, *rr)
+			}
+		}
+	}
+	return rcodeSuccess, answers, extra
+}
@@ -0,0 +1,129 @@
+// Package ingress. Встроенный L4/L7 балансировщик перед репликами сервисов.
+// backends.go -> пулы готовых реплик по (сервис, порт контейнера), выбор реплики
+// (round_robin / least_conn) и учёт открытых соединений для дренажа.
+package ingress
+
+import (
+	"context"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// Backend -> ready replica reachable through published host port
+type Backend struct {
+	TaskID string
+	Addr   string // "<node_ip>:<host_port>"
+}
+
+// Source -> where ready replicas come from
+type Source interface {
+	// Backends -> ready replicas of service publishing container port, false when service is unknown
+	Backends(ctx context.Context, service string, port int) ([]Backend, bool)
+}
+
+// target -> service and container port behind listener or route
+type target struct {
+	service string
+	port    int
+}
+
+// pool -> ready replicas of target
+type pool struct {
+	backends []Backend
+	next     int // round robin position
+}
+
+// pick -> replica for new connection, tried replicas are skipped (failed dial)
+func (p *Proxy) pick(t target, balance types.BalanceStrategy, tried map[string]bool) (Backend, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pl := p.pools[t]
+	if pl == nil || len(pl.backends) == 0 {
+		return Backend{}, false
+	}
+
+	n := len(pl.backends)
+	best := -1
+	for i := 0; i < n; i++ {
+		idx := (pl.next + i) % n
+		b := pl.backends[idx]
+		if tried[b.Addr] {
+			continue
+		}
+		if balance != types.BalanceLeastConn {
+			best = idx
+			break
+		}
+		// Ties go to next replica in round robin order
+		if best == -1 || len(p.conns[b.TaskID]) < len(p.conns[pl.backends[best].TaskID]) {
+			best = idx
+		}
+	}
+	if best == -1 {
+		return Backend{}, false
+	}
+	pl.next = best + 1
+	return pl.backends[best], true
+}
+
+// track -> open connection (or request) to replica, closer ends it when drain times out
+func (p *Proxy) track(taskID string, closer func()) uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastConn++
+	if p.conns[taskID] == nil {
+		p.conns[taskID] = make(map[uint64]func())
+	}
+	p.conns[taskID][p.lastConn] = closer
+	return p.lastConn
+}
+
+// untrack -> connection to replica is finished
+func (p *Proxy) untrack(taskID string, id uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.conns[taskID], id)
+	if len(p.conns[taskID]) == 0 {
+		delete(p.conns, taskID)
+	}
+}
+
+// activeConns -> open connections to replica
+func (p *Proxy) activeConns(taskID string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.conns[taskID])
+}
+
+// refresh -> reload pools of service from source, empty service -> all pools
+func (p *Proxy) refresh(ctx context.Context, service string) {
+	p.mu.Lock()
+	targets := make([]target, 0, len(p.pools))
+	for t := range p.pools {
+		if service == "" || t.service == service {
+			targets = append(targets, t)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, t := range targets {
+		backends, known := p.source.Backends(ctx, t.service, t.port)
+		if !known {
+			p.logger.Warn("ingress target service is unknown", "service", t.service)
+		}
+
+		p.mu.Lock()
+		pl := p.pools[t]
+		if len(pl.backends) != len(backends) {
+			p.logger.Info("ingress backends changed",
+				"service", t.service,
+				"port", t.port,
+				"backends", len(backends))
+		}
+		pl.backends = backends
+		p.mu.Unlock()
+	}
+}
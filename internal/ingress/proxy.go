@@ -0,0 +1,343 @@
+// Package ingress. proxy.go -> слушатели балансировщика: TCP (соединение целиком
+// к одной реплике) и HTTP (маршрут по Host и префиксу пути на каждый запрос),
+// обновление пулов по событиям задач и дренаж соединений останавливаемой задачи.
+package ingress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+const dialTimeout = 5 * time.Second
+
+// Proxy -> listeners of ingress config routing to ready replicas
+type Proxy struct {
+	cfg    types.IngressConfig
+	source Source
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	pools    map[target]*pool
+	conns    map[string]map[uint64]func() // taskID -> open connections
+	lastConn uint64
+
+	refreshCh chan string
+	closers   []io.Closer
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// New -> proxy for listeners of config, backends come from source
+func New(cfg types.IngressConfig, source Source, logger *slog.Logger) *Proxy {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Proxy{
+		cfg:       cfg,
+		source:    source,
+		logger:    logger.With("component", "ingress"),
+		pools:     make(map[target]*pool),
+		conns:     make(map[string]map[uint64]func()),
+		refreshCh: make(chan string, 64),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	for _, l := range cfg.Listeners {
+		if l.Protocol == types.IngressHTTP {
+			for _, r := range l.Routes {
+				p.pools[target{r.Service, r.Port}] = &pool{}
+			}
+			continue
+		}
+		p.pools[target{l.Service, l.Port}] = &pool{}
+	}
+	return p
+}
+
+// Start -> listen on all listeners, nothing is served when any of them fails
+func (p *Proxy) Start() error {
+	listeners := make([]net.Listener, 0, len(p.cfg.Listeners))
+	for _, l := range p.cfg.Listeners {
+		ln, err := net.Listen("tcp", l.ListenAddr)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return fmt.Errorf("ingress: failed to listen %s (%s): %w", l.ListenAddr, l.Name, err)
+		}
+		listeners = append(listeners, ln)
+	}
+
+	p.refresh(p.ctx, "")
+
+	p.wg.Add(1)
+	go p.refreshLoop()
+
+	for i, l := range p.cfg.Listeners {
+		ln := listeners[i]
+		p.wg.Add(1)
+		if l.Protocol == types.IngressHTTP {
+			srv := &http.Server{
+				Handler:           p.httpHandler(l),
+				ReadHeaderTimeout: 10 * time.Second,
+				ErrorLog:          slog.NewLogLogger(p.logger.Handler(), slog.LevelDebug),
+			}
+			p.closers = append(p.closers, srv)
+			go func() {
+				defer p.wg.Done()
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					p.logger.Error("ingress listener failed", "listener", l.Name, "error", err)
+				}
+			}()
+		} else {
+			p.closers = append(p.closers, ln)
+			go func() {
+				defer p.wg.Done()
+				p.serveTCP(ln, l)
+			}()
+		}
+		p.logger.Info("ingress listener started",
+			"listener", l.Name,
+			"addr", l.ListenAddr,
+			"protocol", l.Protocol,
+			"balance", l.Balance)
+	}
+	return nil
+}
+
+// Close -> stop listeners and close open connections
+func (p *Proxy) Close() error {
+	p.cancel()
+	for _, c := range p.closers {
+		c.Close()
+	}
+
+	p.mu.Lock()
+	for _, conns := range p.conns {
+		for _, closer := range conns {
+			closer()
+		}
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	return nil
+}
+
+// Refresh -> task of service changed, pools are reloaded in background
+func (p *Proxy) Refresh(service string) {
+	select {
+	case p.refreshCh <- service:
+	default: // next periodic refresh picks it up
+	}
+}
+
+// Drain -> take task out of rotation and wait for its connections up to drain_timeout or ctx, then close them
+func (p *Proxy) Drain(ctx context.Context, service, taskID string) {
+	// Source no longer reports stopping task -> no new connections after this
+	p.refresh(ctx, service)
+	if p.activeConns(taskID) == 0 {
+		return
+	}
+
+	p.logger.Info("draining task connections",
+		"task_id", taskID,
+		"service", service,
+		"connections", p.activeConns(taskID),
+		"timeout", p.cfg.DrainTimeout)
+
+	deadline := time.NewTimer(p.cfg.DrainTimeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for p.activeConns(taskID) > 0 {
+		select {
+		case <-ticker.C:
+			continue
+		case <-ctx.Done():
+		case <-deadline.C:
+		}
+		break
+	}
+
+	p.mu.Lock()
+	remaining := len(p.conns[taskID])
+	for _, closer := range p.conns[taskID] {
+		closer()
+	}
+	p.mu.Unlock()
+
+	if remaining > 0 {
+		p.logger.Warn("drain timed out, connections closed", "task_id", taskID, "connections", remaining)
+	}
+}
+
+func (p *Proxy) refreshLoop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case service := <-p.refreshCh:
+			p.refresh(p.ctx, service)
+		case <-ticker.C:
+			p.refresh(p.ctx, "")
+		}
+	}
+}
+
+func (p *Proxy) serveTCP(ln net.Listener, l types.IngressListener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				p.logger.Error("ingress listener failed", "listener", l.Name, "error", err)
+			}
+			return
+		}
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.proxyConn(conn, l)
+		}()
+	}
+}
+
+// proxyConn -> whole connection to one replica, replica that refuses dial is skipped
+func (p *Proxy) proxyConn(client net.Conn, l types.IngressListener) {
+	defer client.Close()
+
+	t := target{l.Service, l.Port}
+	tried := make(map[string]bool)
+	for {
+		b, ok := p.pick(t, l.Balance, tried)
+		if !ok {
+			p.logger.Warn("no ready replicas for connection", "listener", l.Name, "service", l.Service)
+			return
+		}
+
+		upstream, err := net.DialTimeout("tcp", b.Addr, dialTimeout)
+		if err != nil {
+			p.logger.Warn("failed to connect to replica", "task_id", b.TaskID, "addr", b.Addr, "error", err)
+			tried[b.Addr] = true
+			continue
+		}
+
+		id := p.track(b.TaskID, func() {
+			client.Close()
+			upstream.Close()
+		})
+		pipe(client, upstream)
+		upstream.Close()
+		p.untrack(b.TaskID, id)
+		return
+	}
+}
+
+// pipe -> copy both directions, half close keeps other direction open
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	copyHalf := func(dst, src net.Conn) {
+		io.Copy(dst, src)
+		if cw, ok := dst.(interface{ CloseWrite() error }); ok {
+			cw.CloseWrite()
+		} else {
+			dst.Close()
+		}
+		done <- struct{}{}
+	}
+	go copyHalf(a, b)
+	go copyHalf(b, a)
+	<-done
+	<-done
+}
+
+// backendKey -> replica address chosen for request
+type backendKey struct{}
+
+// httpHandler -> request routed by host and longest path prefix
+func (p *Proxy) httpHandler(l types.IngressListener) http.Handler {
+	routes := append([]types.IngressRoute(nil), l.Routes...)
+	// Routes with host first, then longer paths
+	sort.SliceStable(routes, func(i, j int) bool {
+		if (routes[i].Host != "") != (routes[j].Host != "") {
+			return routes[i].Host != ""
+		}
+		return len(routes[i].Path) > len(routes[j].Path)
+	})
+
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(&url.URL{Scheme: "http", Host: pr.In.Context().Value(backendKey{}).(string)})
+			pr.SetXForwarded()
+			pr.Out.Host = pr.In.Host // replicas see host of client
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			p.logger.Warn("failed to proxy request",
+				"listener", l.Name,
+				"addr", r.Context().Value(backendKey{}),
+				"error", err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+		ErrorLog: slog.NewLogLogger(p.logger.Handler(), slog.LevelDebug),
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := matchRoute(routes, r)
+		if route == nil {
+			http.Error(w, "no route", http.StatusNotFound)
+			return
+		}
+
+		b, ok := p.pick(target{route.Service, route.Port}, l.Balance, nil)
+		if !ok {
+			http.Error(w, "no ready replicas", http.StatusServiceUnavailable)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(context.WithValue(r.Context(), backendKey{}, b.Addr))
+		defer cancel()
+		id := p.track(b.TaskID, cancel)
+		defer p.untrack(b.TaskID, id)
+
+		rp.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// matchRoute -> first route (sorted) matching host and path prefix on segment boundary
+func matchRoute(routes []types.IngressRoute, r *http.Request) *types.IngressRoute {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for i := range routes {
+		route := &routes[i]
+		if route.Host != "" && !strings.EqualFold(route.Host, host) {
+			continue
+		}
+		path := r.URL.Path
+		if path == route.Path || strings.HasPrefix(path, strings.TrimSuffix(route.Path, "/")+"/") {
+			return route
+		}
+	}
+	return nil
+}
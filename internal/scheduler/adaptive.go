@@ -23,6 +23,19 @@ func (s *SimpleScheduler) SelectNodeAdaptive(ctx context.Context, task *types.Ta
 		}
 	}
 
+	if p := s.placementFor(task); p != nil {
+		nodes = s.filterServiceAffinity(nodes, task, p)
+		if len(nodes) == 0 {
+			return "", fmt.Errorf("no nodes satisfy service affinity rules")
+		}
+
+		nodes = s.filterTopologySpread(nodes, task, p)
+		if len(nodes) == 0 {
+			return "", fmt.Errorf("no nodes satisfy topology spread constraints")
+		}
+		nodes = s.preferTopologySpread(nodes, task, p)
+	}
+
 	switch task.ServiceConfig.ServiceType {
 	case types.ServiceTypeStateless:
 		return s.selectSpreadWithAffinity(nodes, task)
@@ -37,7 +50,7 @@ func (s *SimpleScheduler) SelectNodeAdaptive(ctx context.Context, task *types.Ta
 	}
 }
 
-// ApplyConstraints -> filter nodes by affinity / anti-affinity rules
+// ApplyConstraints -> filter nodes by required affinity / anti-affinity rules
 func (s *SimpleScheduler) applyConstraints(nodes []*types.Node, constraints *types.SchedulingConstraints) []*types.Node {
 	if constraints == nil {
 		return nodes
@@ -46,8 +59,11 @@ func (s *SimpleScheduler) applyConstraints(nodes []*types.Node, constraints *typ
 	result := make([]*types.Node, 0, len(nodes))
 
 	for _, node := range nodes {
-		if s.nodeMatchesAffinity(node, constraints.Affinity) &&
-			s.nodeMatchesAntiAffinity(node, constraints.AntiAffinity) {
+		node.Mu.RLock()
+		matches := constraints.MatchesRequired(node.Labels)
+		node.Mu.RUnlock()
+
+		if matches {
 			result = append(result, node)
 		}
 	}
@@ -55,46 +71,25 @@ func (s *SimpleScheduler) applyConstraints(nodes []*types.Node, constraints *typ
 	return result
 }
 
-func (s *SimpleScheduler) nodeMatchesAffinity(node *types.Node, rules []types.AffinityRule) bool {
-	if len(rules) == 0 {
-		return true
-	}
-
-	for _, rule := range rules {
-		switch rule.Type {
-		case "zone":
-			if zone, exists := node.Labels["zone"]; exists {
-				if rule.Operator == "in" && !contains(rule.Values, zone) {
-					return false
-				}
-			}
-		case "region":
-			if region, exists := node.Labels["region"]; exists {
-				if rule.Operator == "in" && !contains(rule.Values, region) {
-					return false
-				}
-			}
-		}
-	}
-	return true
-}
-
-func (s *SimpleScheduler) nodeMatchesAntiAffinity(node *types.Node, rules []types.AffinityRule) bool {
-	if len(rules) == 0 {
-		return true
+// filterPreferredNodes -> keep only nodes with the best preferred-rules score
+func (s *SimpleScheduler) filterPreferredNodes(nodes []*types.Node, task *types.Task, p *placement) []*types.Node {
+	if task.ServiceConfig == nil || !task.ServiceConfig.SchedulingConstraints.HasPreferred() {
+		return nodes
 	}
 
-	for _, rule := range rules {
-		switch rule.Type {
-		case "zone":
-			if zone, exists := node.Labels["zone"]; exists {
-				if rule.Operator == "in" && contains(rule.Values, zone) {
-					return false
-				}
-			}
+	bestScore := 0
+	result := make([]*types.Node, 0, len(nodes))
+	for _, node := range nodes {
+		score := s.preferenceScore(node, task, p)
+		switch {
+		case len(result) == 0 || score > bestScore:
+			bestScore = score
+			result = append(result[:0], node)
+		case score == bestScore:
+			result = append(result, node)
 		}
 	}
-	return true
+	return result
 }
 
 // SelectSpreadWithAffinity -> distribution taking into account affinity and uniformity
@@ -103,12 +98,18 @@ func (s *SimpleScheduler) selectSpreadWithAffinity(nodes []*types.Node, task *ty
 		return "", fmt.Errorf("no avaliable nodes for stateless service")
 	}
 
+	p := s.placementFor(task)
+	scores := make(map[string]int, len(nodes))
+	for _, node := range nodes {
+		scores[node.ID] = s.preferenceScore(node, task, p)
+	}
+
 	sort.Slice(nodes, func(i, j int) bool {
-		iPreferred := s.isNodePreferred(nodes[i], task)
-		jPreferred := s.isNodePreferred(nodes[j], task)
+		iScore := scores[nodes[i].ID]
+		jScore := scores[nodes[j].ID]
 
-		if iPreferred != jPreferred {
-			return iPreferred
+		if iScore != jScore {
+			return iScore > jScore
 		}
 
 		return nodes[i].TaskCount < nodes[j].TaskCount
@@ -202,21 +203,17 @@ func (s *SimpleScheduler) selectDaemonNode(nodes []*types.Node, task *types.Task
 
 // ========= HELPERS =========
 
-func (s *SimpleScheduler) isNodePreferred(node *types.Node, task *types.Task) bool {
-	if task.ServiceConfig.SchedulingConstraints == nil {
-		return true
+// preferenceScore -> weight of matched preferred label and service rules for the node
+func (s *SimpleScheduler) preferenceScore(node *types.Node, task *types.Task, p *placement) int {
+	if task.ServiceConfig == nil {
+		return 0
 	}
 
-	for _, rule := range task.ServiceConfig.SchedulingConstraints.Affinity {
-		if rule.Type == "zone" {
-			if zone, exists := node.Labels["zone"]; exists {
-				if contains(rule.Values, zone) {
-					return true
-				}
-			}
-		}
-	}
-	return false
+	node.Mu.RLock()
+	score := task.ServiceConfig.SchedulingConstraints.PreferenceScore(node.Labels)
+	node.Mu.RUnlock()
+
+	return score + s.serviceAffinityScore(node, task, p)
 }
 
 func (s *SimpleScheduler) getNodeTasks(nodeID string) []*types.Task {
@@ -237,12 +234,3 @@ func (s *SimpleScheduler) getNodeTasks(nodeID string) []*types.Task {
 
 	return tasks
 }
-
-func contains(slice []string, item string) bool {
-	for _, s := range slice {
-		if s == item {
-			return true
-		}
-	}
-	return false
-}
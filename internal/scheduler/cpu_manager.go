@@ -0,0 +1,301 @@
+// Package scheduler. cpu_manager.go -> учет закрепленных ядер на узлах:
+// выдача эксклюзивных ядер с учетом NUMA и проверка ручных cpu_set.
+// Незакрепленные контейнеры узла работают на общем пуле (ядра узла без закрепленных).
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// cpuManager -> cores of one node and their owners
+type cpuManager struct {
+	order     []int          // cores in layout order
+	numaOf    map[int]int    // core -> NUMA node ID
+	allocated map[int]string // core -> taskID
+}
+
+// newCPUManager -> layout from node NUMA config, or cores 0..CPU/1000-1 in one NUMA node
+func newCPUManager(node *types.Node) *cpuManager {
+	m := &cpuManager{
+		order:     make([]int, 0),
+		numaOf:    make(map[int]int),
+		allocated: make(map[int]string),
+	}
+
+	node.Mu.RLock()
+	defer node.Mu.RUnlock()
+
+	for _, numa := range node.NUMA {
+		cores, err := types.ParseCPUSet(numa.Cores)
+		if err != nil {
+			continue
+		}
+		for _, core := range cores {
+			if _, exists := m.numaOf[core]; !exists {
+				m.order = append(m.order, core)
+				m.numaOf[core] = numa.ID
+			}
+		}
+	}
+
+	if len(m.order) == 0 && node.Resources != nil {
+		for core := 0; core < int(node.Resources.CPU/1000); core++ {
+			m.order = append(m.order, core)
+			m.numaOf[core] = 0
+		}
+	}
+	return m
+}
+
+// freeByNUMA -> free cores grouped by NUMA node
+func (m *cpuManager) freeByNUMA() map[int][]int {
+	result := make(map[int][]int)
+	for _, core := range m.order {
+		if _, taken := m.allocated[core]; !taken {
+			numa := m.numaOf[core]
+			result[numa] = append(result[numa], core)
+		}
+	}
+	return result
+}
+
+// freeCount -> number of free cores
+func (m *cpuManager) freeCount() int {
+	return len(m.order) - len(m.allocated)
+}
+
+// pinnable -> free cores that can be pinned, one always stays in shared pool
+func (m *cpuManager) pinnable() int {
+	return max(m.freeCount()-1, 0)
+}
+
+// sharedPool -> cores of unpinned containers (free cores in layout order)
+func (m *cpuManager) sharedPool() []int {
+	pool := make([]int, 0, m.freeCount())
+	for _, core := range m.order {
+		if _, taken := m.allocated[core]; !taken {
+			pool = append(pool, core)
+		}
+	}
+	return pool
+}
+
+// canReserve -> all cores exist on node and are free, shared pool keeps a core
+func (m *cpuManager) canReserve(cores []int) bool {
+	if len(cores) > m.pinnable() {
+		return false
+	}
+	for _, core := range cores {
+		if _, exists := m.numaOf[core]; !exists {
+			return false
+		}
+		if _, taken := m.allocated[core]; taken {
+			return false
+		}
+	}
+	return true
+}
+
+// pick -> n free cores, one NUMA node if possible (best fit), else fewest NUMA nodes
+func (m *cpuManager) pick(n int) ([]int, error) {
+	if m.pinnable() < n {
+		return nil, fmt.Errorf("only %d cores can be pinned, %d requested", m.pinnable(), n)
+	}
+
+	free := m.freeByNUMA()
+	numaIDs := make([]int, 0, len(free))
+	for id := range free {
+		numaIDs = append(numaIDs, id)
+	}
+
+	// Best fit: smallest NUMA node that still has n free cores
+	sort.Slice(numaIDs, func(i, j int) bool {
+		if len(free[numaIDs[i]]) != len(free[numaIDs[j]]) {
+			return len(free[numaIDs[i]]) < len(free[numaIDs[j]])
+		}
+		return numaIDs[i] < numaIDs[j]
+	})
+	for _, id := range numaIDs {
+		if len(free[id]) >= n {
+			return append([]int(nil), free[id][:n]...), nil
+		}
+	}
+
+	// Span NUMA nodes starting from the most free ones
+	result := make([]int, 0, n)
+	for i := len(numaIDs) - 1; i >= 0 && len(result) < n; i-- {
+		cores := free[numaIDs[i]]
+		need := n - len(result)
+		if need > len(cores) {
+			need = len(cores)
+		}
+		result = append(result, cores[:need]...)
+	}
+	return result, nil
+}
+
+// cpuManagerFor -> manager of node, created on first use (cpuMu must be locked)
+func (s *SimpleScheduler) cpuManagerFor(node *types.Node) *cpuManager {
+	m, exists := s.cpuManagers[node.ID]
+	if !exists {
+		m = newCPUManager(node)
+		s.cpuManagers[node.ID] = m
+	}
+	return m
+}
+
+// resizeCPUManager -> node capacity changed, new layout keeps pinned cores that still exist
+func (s *SimpleScheduler) resizeCPUManager(node *types.Node) {
+	s.cpuMu.Lock()
+	defer s.cpuMu.Unlock()
+
+	old, exists := s.cpuManagers[node.ID]
+	if !exists {
+		return
+	}
+	m := newCPUManager(node)
+	for core, taskID := range old.allocated {
+		if _, exists := m.numaOf[core]; exists {
+			m.allocated[core] = taskID
+			continue
+		}
+		s.logger.Warn("pinned core no longer exists on node",
+			"node_id", node.ID,
+			"task_id", taskID,
+			"core", core)
+	}
+	s.cpuManagers[node.ID] = m
+}
+
+// needsPinning -> task asks for exclusive cores or manual cpu_set
+func needsPinning(task *types.Task) bool {
+	return task.ServiceConfig != nil &&
+		(task.ServiceConfig.Resources.ExclusiveCPUs > 0 || task.ServiceConfig.Resources.CPUSet != "")
+}
+
+// filterCPUPinning -> only nodes with enough free cores / free manual cpu_set
+func (s *SimpleScheduler) filterCPUPinning(nodes []*types.Node, task *types.Task) []*types.Node {
+	if !needsPinning(task) {
+		return nodes
+	}
+
+	resources := task.ServiceConfig.Resources
+	manual, err := types.ParseCPUSet(resources.CPUSet)
+	if err != nil {
+		return []*types.Node{}
+	}
+
+	s.cpuMu.Lock()
+	defer s.cpuMu.Unlock()
+
+	result := make([]*types.Node, 0, len(nodes))
+	for _, node := range nodes {
+		m := s.cpuManagerFor(node)
+		if resources.ExclusiveCPUs > 0 && m.pinnable() < resources.ExclusiveCPUs {
+			continue
+		}
+		if len(manual) > 0 && !m.canReserve(manual) {
+			continue
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+// allocateCPUs -> reserve cores on node and write them into task.CPUSet,
+// unpinned task gets shared pool of node with CPU manager
+func (s *SimpleScheduler) allocateCPUs(node *types.Node, task *types.Task) error {
+	s.cpuMu.Lock()
+	defer s.cpuMu.Unlock()
+
+	if !needsPinning(task) {
+		if m, exists := s.cpuManagers[node.ID]; exists {
+			task.CPUSet = types.FormatCPUSet(m.sharedPool())
+		}
+		return nil
+	}
+
+	resources := task.ServiceConfig.Resources
+
+	m := s.cpuManagerFor(node)
+
+	var cores []int
+	if resources.ExclusiveCPUs > 0 {
+		picked, err := m.pick(resources.ExclusiveCPUs)
+		if err != nil {
+			return fmt.Errorf("cannot pin cores on node %s: %w", node.ID, err)
+		}
+		cores = picked
+	} else {
+		manual, err := types.ParseCPUSet(resources.CPUSet)
+		if err != nil {
+			return err
+		}
+		if !m.canReserve(manual) {
+			return fmt.Errorf("cpu_set %s conflicts with cores allocated on node %s", resources.CPUSet, node.ID)
+		}
+		cores = manual
+	}
+
+	for _, core := range cores {
+		m.allocated[core] = task.ID
+	}
+	task.CPUSet = types.FormatCPUSet(cores)
+
+	s.logger.Debug("cores pinned",
+		"node_id", node.ID,
+		"task_id", task.ID,
+		"cpu_set", task.CPUSet)
+
+	return nil
+}
+
+// releaseCPUs -> free all cores of the task on node
+func (s *SimpleScheduler) releaseCPUs(nodeID, taskID string) {
+	s.cpuMu.Lock()
+	defer s.cpuMu.Unlock()
+
+	m, exists := s.cpuManagers[nodeID]
+	if !exists {
+		return
+	}
+	for core, owner := range m.allocated {
+		if owner == taskID {
+			delete(m.allocated, core)
+		}
+	}
+}
+
+// SharedCPUSet -> cores of unpinned containers on node ("" when node has no CPU manager)
+func (s *SimpleScheduler) SharedCPUSet(nodeID string) string {
+	s.cpuMu.Lock()
+	defer s.cpuMu.Unlock()
+
+	m, exists := s.cpuManagers[nodeID]
+	if !exists {
+		return ""
+	}
+	return types.FormatCPUSet(m.sharedPool())
+}
+
+// GetAllocatedCPUs -> pinned cores on node (taskID -> "0-3")
+func (s *SimpleScheduler) GetAllocatedCPUs(nodeID string) map[string]string {
+	s.cpuMu.Lock()
+	defer s.cpuMu.Unlock()
+
+	byTask := make(map[string][]int)
+	if m, exists := s.cpuManagers[nodeID]; exists {
+		for core, taskID := range m.allocated {
+			byTask[taskID] = append(byTask[taskID], core)
+		}
+	}
+
+	result := make(map[string]string, len(byTask))
+	for taskID, cores := range byTask {
+		result[taskID] = types.FormatCPUSet(cores)
+	}
+	return result
+}
@@ -0,0 +1,166 @@
+// Package scheduler. ports.go -> учет портов хоста на узлах:
+// обнаружение конфликтов и выдача динамических портов из диапазона.
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+const (
+	// Default range for dynamic host ports
+	DefaultPortRangeStart = 30000
+	DefaultPortRangeEnd   = 32767
+)
+
+// portKey -> "tcp/8080"
+func portKey(protocol types.Protocol, port int) string {
+	if protocol == "" {
+		protocol = types.TCP
+	}
+	return fmt.Sprintf("%s/%d", protocol, port)
+}
+
+// filterPortConflicts -> only nodes where fixed host ports are free and dynamic ports are left
+func (s *SimpleScheduler) filterPortConflicts(nodes []*types.Node, task *types.Task) []*types.Node {
+	if task.ServiceConfig == nil || len(task.ServiceConfig.Ports) == 0 {
+		return nodes
+	}
+
+	s.portsMu.Lock()
+	defer s.portsMu.Unlock()
+
+	result := make([]*types.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if s.portsAvailable(node.ID, task.ServiceConfig.Ports) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// portsAvailable -> check ports on node (portsMu must be locked)
+func (s *SimpleScheduler) portsAvailable(nodeID string, ports []types.PortMapping) bool {
+	allocated := s.portAllocations[nodeID]
+
+	dynamic := 0
+	for _, pm := range ports {
+		if pm.HostPort == 0 {
+			dynamic++
+			continue
+		}
+		if _, taken := allocated[portKey(pm.Protocol, pm.HostPort)]; taken {
+			return false
+		}
+	}
+
+	if dynamic == 0 {
+		return true
+	}
+
+	free := 0
+	for port := s.config.PortRangeStart; port <= s.config.PortRangeEnd && free < dynamic; port++ {
+		if _, taken := allocated[portKey(types.TCP, port)]; taken {
+			continue
+		}
+		if _, taken := allocated[portKey(types.UDP, port)]; taken {
+			continue
+		}
+		free++
+	}
+	return free >= dynamic
+}
+
+// allocatePorts -> reserve host ports on node and write actual ports into task.PortMapping
+func (s *SimpleScheduler) allocatePorts(nodeID string, task *types.Task) error {
+	if task.ServiceConfig == nil || len(task.ServiceConfig.Ports) == 0 {
+		return nil
+	}
+
+	s.portsMu.Lock()
+	defer s.portsMu.Unlock()
+
+	if !s.portsAvailable(nodeID, task.ServiceConfig.Ports) {
+		return fmt.Errorf("host ports are not available on node %s", nodeID)
+	}
+
+	allocated, exists := s.portAllocations[nodeID]
+	if !exists {
+		allocated = make(map[string]string)
+		s.portAllocations[nodeID] = allocated
+	}
+
+	mapping := make([]types.PortMapping, len(task.ServiceConfig.Ports))
+	copy(mapping, task.ServiceConfig.Ports)
+
+	// Fixed ports first, dynamic ports must not steal them
+	for _, pm := range mapping {
+		if pm.HostPort != 0 {
+			allocated[portKey(pm.Protocol, pm.HostPort)] = task.ID
+		}
+	}
+
+	next := s.config.PortRangeStart
+	for i := range mapping {
+		if mapping[i].HostPort != 0 {
+			continue
+		}
+		for ; next <= s.config.PortRangeEnd; next++ {
+			_, tcpTaken := allocated[portKey(types.TCP, next)]
+			_, udpTaken := allocated[portKey(types.UDP, next)]
+			if !tcpTaken && !udpTaken {
+				break
+			}
+		}
+		if next > s.config.PortRangeEnd {
+			for key, owner := range allocated {
+				if owner == task.ID {
+					delete(allocated, key)
+				}
+			}
+			return fmt.Errorf("dynamic host port range %d-%d is exhausted on node %s",
+				s.config.PortRangeStart, s.config.PortRangeEnd, nodeID)
+		}
+		mapping[i].HostPort = next
+		allocated[portKey(mapping[i].Protocol, next)] = task.ID
+		next++
+	}
+
+	task.PortMapping = mapping
+
+	s.logger.Debug("host ports allocated",
+		"node_id", nodeID,
+		"task_id", task.ID,
+		"ports", mapping)
+
+	return nil
+}
+
+// releasePorts -> free all host ports of the task on node
+func (s *SimpleScheduler) releasePorts(nodeID, taskID string) {
+	s.portsMu.Lock()
+	defer s.portsMu.Unlock()
+
+	allocated := s.portAllocations[nodeID]
+	for key, owner := range allocated {
+		if owner == taskID {
+			delete(allocated, key)
+		}
+	}
+	if len(allocated) == 0 {
+		delete(s.portAllocations, nodeID)
+	}
+}
+
+// GetAllocatedPorts -> allocated host ports on node ("tcp/8080" -> taskID)
+func (s *SimpleScheduler) GetAllocatedPorts(nodeID string) map[string]string {
+	s.portsMu.Lock()
+	defer s.portsMu.Unlock()
+
+	result := make(map[string]string, len(s.portAllocations[nodeID]))
+	for key, taskID := range s.portAllocations[nodeID] {
+		result[key] = taskID
+	}
+	return result
+}
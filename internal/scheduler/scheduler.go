@@ -38,21 +38,35 @@ const (
 	StrategyLeastResource Strategy = "least_resource"
 )
 
+// Strategies -> all strategies (simulation compares them side by side)
+var Strategies = []Strategy{
+	StrategyRandom,
+	StrategyRoundRobin,
+	StrategyBinpack,
+	StrategySpread,
+	StrategyLeastTasks,
+	StrategyLeastResource,
+}
+
 // Scheduler config -> Local structure for Scheduler
 type SchedulerConfig struct {
-	Strategy           Strategy      // strategy
-	HeartbeatTimeout   time.Duration // timeout of heartbeat
-	CleanupInterval    time.Duration // delete (cleanup)
-	ResourceOvercommit float64       // koef overcommit (1.0 = 100%)
+	Strategy         Strategy               // strategy
+	HeartbeatTimeout time.Duration          // timeout of heartbeat
+	CleanupInterval  time.Duration          // delete (cleanup)
+	Overcommit       types.OvercommitRatios // capacity multipliers (1.0 = no overcommit), node ratios override
+	PortRangeStart   int                    // dynamic host ports: first
+	PortRangeEnd     int                    // dynamic host ports: last
 }
 
 // DefaultConfig -> default
 func DefaultConfig() *SchedulerConfig {
 	return &SchedulerConfig{
-		Strategy:           StrategySpread,
-		HeartbeatTimeout:   30 * time.Second,
-		CleanupInterval:    1 * time.Minute,
-		ResourceOvercommit: 1.0, // no overcommit
+		Strategy:         StrategySpread,
+		HeartbeatTimeout: 30 * time.Second,
+		CleanupInterval:  10 * time.Second,
+		Overcommit:       types.OvercommitRatios{CPU: 1.0, Memory: 1.0}, // no overcommit
+		PortRangeStart:   DefaultPortRangeStart,
+		PortRangeEnd:     DefaultPortRangeEnd,
 	}
 }
 
@@ -69,6 +83,14 @@ type SimpleScheduler struct {
 	heartbeatWorkers map[string]context.CancelFunc // nodeID: cancelFunc
 	heartbeatMu      sync.Mutex
 
+	// Host ports
+	portAllocations map[string]map[string]string // nodeID -> "tcp/8080" -> taskID
+	portsMu         sync.Mutex
+
+	// Pinned cores
+	cpuManagers map[string]*cpuManager // nodeID -> cores
+	cpuMu       sync.Mutex
+
 	taskStore store.TaskStore
 	logger    *slog.Logger
 	ctx       context.Context
@@ -84,6 +106,10 @@ func New(config *SchedulerConfig, logger *slog.Logger, nodesConfig []types.NodeC
 	if logger == nil {
 		logger = slog.Default()
 	}
+	if config.PortRangeStart == 0 || config.PortRangeEnd == 0 {
+		config.PortRangeStart = DefaultPortRangeStart
+		config.PortRangeEnd = DefaultPortRangeEnd
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -92,6 +118,8 @@ func New(config *SchedulerConfig, logger *slog.Logger, nodesConfig []types.NodeC
 		nodes:            make(map[string]*types.Node),
 		roundRobinIndex:  make(map[string]int),
 		heartbeatWorkers: make(map[string]context.CancelFunc),
+		portAllocations:  make(map[string]map[string]string),
+		cpuManagers:      make(map[string]*cpuManager),
 		taskStore:        taskStore,
 		logger:           logger.With("component", "scheduler"),
 		ctx:              ctx,
@@ -118,29 +146,14 @@ func (s *SimpleScheduler) loadNodesFromConfig(nodesConfig []types.NodeConfig) {
 	defer s.mu.Unlock()
 
 	for _, nc := range nodesConfig {
-		node := &types.Node{
-			ID:       nc.ID,
-			Hostname: nc.Hostname,
-			IP:       nc.IP,
-			Status:   types.NodeStatusReady,
-			Resources: &types.NodeResources{
-				CPU:    nc.CPU,
-				Memory: nc.Memory,
-			},
-			Labels:   nc.Labels,
-			LastSeen: time.Now(),
-		}
-
-		if node.Labels == nil {
-			node.Labels = make(map[string]string)
-		}
-
+		node := types.NewNode(nc)
 		s.nodes[node.ID] = node
 		s.logger.Info("node loaded from config",
 			"node_id", node.ID,
 			"hostname", node.Hostname,
 			"cpu", node.Resources.CPU,
-			"memory", node.Resources.Memory)
+			"memory", node.Resources.Memory,
+			"disk", node.Resources.Disk)
 	}
 }
 
@@ -197,46 +210,41 @@ func (s *SimpleScheduler) cleanupLoop() {
 	}
 }
 
-// cleanupInactiveNodes -> delete nodes that not send heartbeat for a while
+// cleanupInactiveNodes -> nodes that not send heartbeat for a while become NotReady
 func (s *SimpleScheduler) cleanupInactiveNodes() {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	now := time.Now()
 	timeout := s.config.HeartbeatTimeout
 
 	for id, node := range s.nodes {
-		if now.Sub(node.LastSeen) > timeout {
-			s.logger.Warn("node inactive, removing", "node_id", id, "last_seen", node.LastSeen)
-			delete(s.nodes, id)
-			go s.stopHeartbeatWorker(id)
+		node.Mu.Lock()
+		if node.Status == types.NodeStatusReady && now.Sub(node.LastSeen) > timeout {
+			setNodeStatus(node, types.NodeStatusNotReady)
+			s.logger.Warn("node missed heartbeats, marked not ready",
+				"node_id", id,
+				"last_seen", node.LastSeen)
 		}
+		node.Mu.Unlock()
 	}
 }
 
 // SelectNode -> choose Node by chosen strategy
 func (s *SimpleScheduler) SelectNode(ctx context.Context, task *types.Task, nodes []*types.Node) (string, error) {
-	if len(nodes) == 0 {
-		return "", errors.New("no nodes available")
-	}
-
-	// Only READY Nodes
-	readyNodes := s.filterReadyNodes(nodes)
-	if len(readyNodes) == 0 {
-		return "", errors.New("no ready nodes")
+	feasibleNodes, p, err := s.filterNodes(task, nodes)
+	if err != nil {
+		return "", err
 	}
 
-	// We have resources?
-	feasibleNodes := s.filterFeasibleNodes(readyNodes, task)
-	if len(feasibleNodes) == 0 {
-		return "", fmt.Errorf("no nodes with sufficient resources for task (req CPU: %dm, Mem: %d)",
-			task.ServiceConfig.Resources.CPUMilliCores,
-			task.ServiceConfig.Resources.MemoryBytes)
+	// Preferred rules -> strategy chooses among the best scored nodes
+	feasibleNodes = s.filterPreferredNodes(feasibleNodes, task, p)
+	if p != nil {
+		feasibleNodes = s.preferTopologySpread(feasibleNodes, task, p)
 	}
 
 	// Choose Node
 	var selectedNode *types.Node
-	var err error
 
 	switch s.config.Strategy {
 	case StrategyRandom:
@@ -259,6 +267,17 @@ func (s *SimpleScheduler) SelectNode(ctx context.Context, task *types.Task, node
 		return "", err
 	}
 
+	// Reserve host ports -> task.PortMapping gets actual ports
+	if err := s.allocatePorts(selectedNode.ID, task); err != nil {
+		return "", err
+	}
+
+	// Pin cores -> task.CPUSet gets actual cores
+	if err := s.allocateCPUs(selectedNode, task); err != nil {
+		s.releasePorts(selectedNode.ID, task.ID)
+		return "", err
+	}
+
 	// Update Node resources
 	s.updateNodeResources(selectedNode.ID, task)
 
@@ -271,17 +290,108 @@ func (s *SimpleScheduler) SelectNode(ctx context.Context, task *types.Task, node
 	return selectedNode.ID, nil
 }
 
+// FilterNodes -> nodes where task can be placed right now (hard rules only, nothing is reserved)
+func (s *SimpleScheduler) FilterNodes(ctx context.Context, task *types.Task, nodes []*types.Node) ([]*types.Node, error) {
+	feasibleNodes, _, err := s.filterNodes(task, nodes)
+	return feasibleNodes, err
+}
+
+// filterNodes -> ready, constraints, service rules, spread, ports and resources filters
+func (s *SimpleScheduler) filterNodes(task *types.Task, nodes []*types.Node) ([]*types.Node, *placement, error) {
+	if len(nodes) == 0 {
+		return nil, nil, errors.New("no nodes available")
+	}
+
+	// Only READY Nodes
+	readyNodes := s.filterReadyNodes(nodes)
+	if len(readyNodes) == 0 {
+		return nil, nil, errors.New("no ready nodes")
+	}
+
+	// Local volumes of task live on one node
+	readyNodes = s.filterVolumeNodes(readyNodes, task)
+	if len(readyNodes) == 0 {
+		return nil, nil, errors.New("node holding volumes of task is not available")
+	}
+
+	// Required affinity / anti-affinity rules
+	if task.ServiceConfig != nil && task.ServiceConfig.SchedulingConstraints != nil {
+		readyNodes = s.applyConstraints(readyNodes, task.ServiceConfig.SchedulingConstraints)
+		if len(readyNodes) == 0 {
+			return nil, nil, errors.New("no nodes satisfy scheduling constraints")
+		}
+	}
+
+	// Rules relative to other services placement
+	p := s.placementFor(task)
+	if p != nil {
+		readyNodes = s.filterServiceAffinity(readyNodes, task, p)
+		if len(readyNodes) == 0 {
+			return nil, nil, errors.New("no nodes satisfy service affinity rules")
+		}
+
+		readyNodes = s.filterTopologySpread(readyNodes, task, p)
+		if len(readyNodes) == 0 {
+			return nil, nil, errors.New("no nodes satisfy topology spread constraints")
+		}
+	}
+
+	// Fixed host ports must be free on node
+	readyNodes = s.filterPortConflicts(readyNodes, task)
+	if len(readyNodes) == 0 {
+		return nil, nil, errors.New("no nodes with free host ports for task")
+	}
+
+	// Pinned cores must be free on node
+	readyNodes = s.filterCPUPinning(readyNodes, task)
+	if len(readyNodes) == 0 {
+		return nil, nil, errors.New("no nodes with free cores for pinned task")
+	}
+
+	// We have resources?
+	feasibleNodes := s.filterFeasibleNodes(readyNodes, task)
+	if len(feasibleNodes) == 0 {
+		return nil, nil, fmt.Errorf("no nodes with sufficient resources for task (req CPU: %dm, Mem: %d, Disk: %d)",
+			task.ServiceConfig.Resources.CPURequest(),
+			task.ServiceConfig.Resources.MemoryRequest(),
+			task.ServiceConfig.Resources.DiskBytes)
+	}
+
+	return feasibleNodes, p, nil
+}
+
 // filterReadyNodes -> Only READY Nodes
 func (s *SimpleScheduler) filterReadyNodes(nodes []*types.Node) []*types.Node {
 	result := make([]*types.Node, 0, len(nodes))
 	for _, node := range nodes {
-		if node.Status == types.NodeStatusReady {
+		node.Mu.RLock()
+		ready := node.Status == types.NodeStatusReady && !node.Unschedulable
+		node.Mu.RUnlock()
+		if ready {
 			result = append(result, node)
 		}
 	}
 	return result
 }
 
+// filterVolumeNodes -> only node every bound volume of task is on
+func (s *SimpleScheduler) filterVolumeNodes(nodes []*types.Node, task *types.Task) []*types.Node {
+	result := nodes
+	for _, v := range task.Volumes {
+		if v.NodeID == "" {
+			continue
+		}
+		pinned := make([]*types.Node, 0, 1)
+		for _, node := range result {
+			if node.ID == v.NodeID {
+				pinned = append(pinned, node)
+			}
+		}
+		result = pinned
+	}
+	return result
+}
+
 // filterFeasibleNodes -> only Nodes with resources
 func (s *SimpleScheduler) filterFeasibleNodes(nodes []*types.Node, task *types.Task) []*types.Node {
 	// Nil check
@@ -289,27 +399,64 @@ func (s *SimpleScheduler) filterFeasibleNodes(nodes []*types.Node, task *types.T
 		return []*types.Node{}
 	}
 
-	reqCPU := task.ServiceConfig.Resources.CPUMilliCores
-	reqMem := task.ServiceConfig.Resources.MemoryBytes
-
-	// + overcommit
-	maxCPU := int64(float64(reqCPU) * s.config.ResourceOvercommit)
-	maxMem := int64(float64(reqMem) * s.config.ResourceOvercommit)
+	reqCPU := task.ServiceConfig.Resources.CPURequest()
+	reqMem := task.ServiceConfig.Resources.MemoryRequest()
+	reqDisk := task.ServiceConfig.Resources.DiskBytes
 
 	result := make([]*types.Node, 0, len(nodes))
 	for _, node := range nodes {
 		node.Mu.RLock()
-		availableCPU := node.Resources.CPU - node.UsedCPU
-		availableMem := node.Resources.Memory - node.UsedMemory
+		// Requests against capacity with overcommit
+		capCPU, capMem := s.nodeCapacity(node)
+		availableCPU := capCPU - node.UsedCPU
+		availableMem := capMem - node.UsedMemory
+		// Disk is not overcommitted, node without disk capacity is not accounted
+		diskFits := node.Resources.Disk == 0 || node.Resources.Disk-node.UsedDisk >= reqDisk
+
+		extendedFits := extendedResourcesFit(node, task.ServiceConfig.Resources.Extended)
 		node.Mu.RUnlock()
 
-		if availableCPU >= maxCPU && availableMem >= maxMem {
+		if availableCPU >= reqCPU && availableMem >= reqMem && diskFits && extendedFits {
 			result = append(result, node)
 		}
 	}
 	return result
 }
 
+// nodeCapacity -> cpu / memory capacity multiplied by node (or default) overcommit ratios
+func (s *SimpleScheduler) nodeCapacity(node *types.Node) (int64, int64) {
+	cpuRatio := node.Overcommit.CPU
+	if cpuRatio <= 0 {
+		cpuRatio = s.config.Overcommit.CPU
+	}
+	if cpuRatio <= 0 {
+		cpuRatio = 1.0
+	}
+
+	memRatio := node.Overcommit.Memory
+	if memRatio <= 0 {
+		memRatio = s.config.Overcommit.Memory
+	}
+	if memRatio <= 0 {
+		memRatio = 1.0
+	}
+
+	return int64(float64(node.Resources.CPU) * cpuRatio), int64(float64(node.Resources.Memory) * memRatio)
+}
+
+// extendedResourcesFit -> every requested extended resource is left on node (node.Mu must be locked)
+func extendedResourcesFit(node *types.Node, requested map[string]int64) bool {
+	for name, amount := range requested {
+		if amount <= 0 {
+			continue
+		}
+		if node.Resources.Extended[name]-node.UsedExtended[name] < amount {
+			return false
+		}
+	}
+	return true
+}
+
 // selectRandom -> choose random Nodes
 func (s *SimpleScheduler) selectRandom(nodes []*types.Node) (*types.Node, error) {
 	if len(nodes) == 0 {
@@ -348,15 +495,17 @@ func (s *SimpleScheduler) selectBinpack(nodes []*types.Node, task *types.Task) (
 		return nil, errors.New("no nodes to select from")
 	}
 
-	reqCPU := task.ServiceConfig.Resources.CPUMilliCores
-	reqMem := task.ServiceConfig.Resources.MemoryBytes
+	reqCPU := task.ServiceConfig.Resources.CPURequest()
+	reqMem := task.ServiceConfig.Resources.MemoryRequest()
 
 	// Sort by min resource
 	sort.Slice(nodes, func(i, j int) bool {
-		iCPULeft := nodes[i].Resources.CPU - nodes[i].UsedCPU - reqCPU
-		iMemLeft := nodes[i].Resources.Memory - nodes[i].UsedMemory - reqMem
-		jCPULeft := nodes[j].Resources.CPU - nodes[j].UsedCPU - reqCPU
-		jMemLeft := nodes[j].Resources.Memory - nodes[j].UsedMemory - reqMem
+		iCPUCap, iMemCap := s.nodeCapacity(nodes[i])
+		jCPUCap, jMemCap := s.nodeCapacity(nodes[j])
+		iCPULeft := iCPUCap - nodes[i].UsedCPU - reqCPU
+		iMemLeft := iMemCap - nodes[i].UsedMemory - reqMem
+		jCPULeft := jCPUCap - nodes[j].UsedCPU - reqCPU
+		jMemLeft := jMemCap - nodes[j].UsedMemory - reqMem
 
 		// By CPU -> By memory
 		if iCPULeft == jCPULeft {
@@ -393,8 +542,8 @@ func (s *SimpleScheduler) selectLeastResource(nodes []*types.Node, task *types.T
 		return nil, errors.New("no nodes to select from")
 	}
 
-	reqCPU := task.ServiceConfig.Resources.CPUMilliCores
-	reqMem := task.ServiceConfig.Resources.MemoryBytes
+	reqCPU := task.ServiceConfig.Resources.CPURequest()
+	reqMem := task.ServiceConfig.Resources.MemoryRequest()
 
 	if reqCPU == 0 {
 		reqCPU = 1
@@ -405,10 +554,12 @@ func (s *SimpleScheduler) selectLeastResource(nodes []*types.Node, task *types.T
 
 	// Sort by max Resource
 	sort.Slice(nodes, func(i, j int) bool {
-		iCPUFree := nodes[i].Resources.CPU - nodes[i].UsedCPU
-		iMemFree := nodes[i].Resources.Memory - nodes[i].UsedMemory
-		jCPUFree := nodes[j].Resources.CPU - nodes[j].UsedCPU
-		jMemFree := nodes[j].Resources.Memory - nodes[j].UsedMemory
+		iCPUCap, iMemCap := s.nodeCapacity(nodes[i])
+		jCPUCap, jMemCap := s.nodeCapacity(nodes[j])
+		iCPUFree := iCPUCap - nodes[i].UsedCPU
+		iMemFree := iMemCap - nodes[i].UsedMemory
+		jCPUFree := jCPUCap - nodes[j].UsedCPU
+		jMemFree := jMemCap - nodes[j].UsedMemory
 
 		// Normalize and compare
 		iScore := float64(iCPUFree)/float64(reqCPU) + float64(iMemFree)/float64(reqMem)
@@ -433,8 +584,15 @@ func (s *SimpleScheduler) updateNodeResources(nodeID string, task *types.Task) {
 	node.Mu.Lock()
 	defer node.Mu.Unlock()
 
-	node.UsedCPU += task.ServiceConfig.Resources.CPUMilliCores
-	node.UsedMemory += task.ServiceConfig.Resources.MemoryBytes
+	node.UsedCPU += task.ServiceConfig.Resources.CPURequest()
+	node.UsedMemory += task.ServiceConfig.Resources.MemoryRequest()
+	node.UsedDisk += task.ServiceConfig.Resources.DiskBytes
+	for name, amount := range task.ServiceConfig.Resources.Extended {
+		if node.UsedExtended == nil {
+			node.UsedExtended = make(map[string]int64)
+		}
+		node.UsedExtended[name] += amount
+	}
 	node.TaskCount++
 	node.LastSeen = time.Now()
 }
@@ -475,6 +633,8 @@ func (s *SimpleScheduler) RegisterNode(ctx context.Context, node *types.Node) er
 	node.TaskCount = 0
 	node.UsedCPU = 0
 	node.UsedMemory = 0
+	node.UsedDisk = 0
+	node.UsedExtended = make(map[string]int64)
 	s.nodes[node.ID] = node
 
 	s.startHeatbeatWorker(node.ID)
@@ -494,6 +654,14 @@ func (s *SimpleScheduler) UnregisterNode(ctx context.Context, nodeID string) err
 
 	s.stopHeartbeatWorker(nodeID)
 
+	s.cpuMu.Lock()
+	delete(s.cpuManagers, nodeID)
+	s.cpuMu.Unlock()
+
+	s.portsMu.Lock()
+	delete(s.portAllocations, nodeID)
+	s.portsMu.Unlock()
+
 	delete(s.nodes, nodeID)
 	s.logger.Info("node unregistered", "node_id", nodeID)
 	return nil
@@ -512,13 +680,114 @@ func (s *SimpleScheduler) UpdateNodeStatus(ctx context.Context, nodeID string, s
 	node.Mu.Lock()
 	defer node.Mu.Unlock()
 
-	node.Status = status
+	setNodeStatus(node, status)
 	node.LastSeen = time.Now()
 
 	s.logger.Debug("node status updated", "node_id", nodeID, "status", status)
 	return nil
 }
 
+// UpdateNode -> change labels and capacity of node, running tasks stay
+func (s *SimpleScheduler) UpdateNode(ctx context.Context, nodeID string, update types.NodeUpdate) error {
+	s.mu.RLock()
+	node, exists := s.nodes[nodeID]
+	s.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("node with ID %s not found", nodeID)
+	}
+
+	if update.CPU != nil && *update.CPU <= 0 {
+		return fmt.Errorf("cpu must be positive")
+	}
+	if update.Memory != nil && *update.Memory <= 0 {
+		return fmt.Errorf("memory must be positive")
+	}
+	if update.Disk != nil && *update.Disk < 0 {
+		return fmt.Errorf("disk can't be negative")
+	}
+	for key := range update.Labels {
+		if key == "" {
+			return fmt.Errorf("label key can't be empty")
+		}
+	}
+	for name, capacity := range update.Extended {
+		if name == "" || (capacity != nil && *capacity < 0) {
+			return fmt.Errorf("extended resource %q: name is required and capacity can't be negative", name)
+		}
+	}
+
+	node.Mu.Lock()
+	// New maps -> readers that took old map keep consistent view
+	if update.Labels != nil {
+		labels := make(map[string]string, len(node.Labels)+len(update.Labels))
+		for key, value := range node.Labels {
+			labels[key] = value
+		}
+		for key, value := range update.Labels {
+			if value == nil {
+				delete(labels, key)
+			} else {
+				labels[key] = *value
+			}
+		}
+		node.Labels = labels
+	}
+
+	resources := *node.Resources
+	if update.CPU != nil {
+		resources.CPU = *update.CPU
+	}
+	if update.Memory != nil {
+		resources.Memory = *update.Memory
+	}
+	if update.Disk != nil {
+		resources.Disk = *update.Disk
+	}
+	if update.Extended != nil {
+		extended := make(map[string]int64, len(resources.Extended)+len(update.Extended))
+		for name, capacity := range resources.Extended {
+			extended[name] = capacity
+		}
+		for name, capacity := range update.Extended {
+			if capacity == nil {
+				delete(extended, name)
+			} else {
+				extended[name] = *capacity
+			}
+		}
+		resources.Extended = extended
+	}
+	cpuChanged := resources.CPU != node.Resources.CPU
+	node.Resources = &resources
+	node.Mu.Unlock()
+
+	if cpuChanged {
+		s.resizeCPUManager(node)
+	}
+
+	s.logger.Info("node updated", "node_id", nodeID)
+	return nil
+}
+
+// SetUnschedulable -> stop or resume new placements on node
+func (s *SimpleScheduler) SetUnschedulable(ctx context.Context, nodeID string, unschedulable bool) error {
+	s.mu.RLock()
+	node, exists := s.nodes[nodeID]
+	s.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("node with ID %s not found", nodeID)
+	}
+
+	node.Mu.Lock()
+	node.Unschedulable = unschedulable
+	node.Mu.Unlock()
+
+	s.logger.Info("node schedulability changed", "node_id", nodeID, "unschedulable", unschedulable)
+	return nil
+}
+
 // GetNode -> Get Node By ID
 func (s *SimpleScheduler) GetNode(ctx context.Context, nodeID string) (*types.Node, error) {
 	s.mu.RLock()
@@ -566,28 +835,51 @@ func (s *SimpleScheduler) ReleaseNodeResources(ctx context.Context, nodeID strin
 	defer node.Mu.Unlock()
 
 	// Clear CPU
-	node.UsedCPU -= task.ServiceConfig.Resources.CPUMilliCores
+	node.UsedCPU -= task.ServiceConfig.Resources.CPURequest()
 	if node.UsedCPU < 0 {
 		node.UsedCPU = 0
 	}
 
 	// Free MEM
-	node.UsedMemory -= task.ServiceConfig.Resources.MemoryBytes
+	node.UsedMemory -= task.ServiceConfig.Resources.MemoryRequest()
 	if node.UsedMemory < 0 {
 		node.UsedMemory = 0
 	}
 
+	// Free DISK
+	node.UsedDisk -= task.ServiceConfig.Resources.DiskBytes
+	if node.UsedDisk < 0 {
+		node.UsedDisk = 0
+	}
+
+	// Free EXTENDED
+	if node.UsedExtended != nil {
+		for name, amount := range task.ServiceConfig.Resources.Extended {
+			node.UsedExtended[name] -= amount
+			if node.UsedExtended[name] <= 0 {
+				delete(node.UsedExtended, name)
+			}
+		}
+	}
+
 	// Decr task counter
 	node.TaskCount--
 	if node.TaskCount < 0 {
 		node.TaskCount = 0
 	}
 
+	// Free host ports
+	s.releasePorts(nodeID, task.ID)
+
+	// Free pinned cores
+	s.releaseCPUs(nodeID, task.ID)
+
 	s.logger.Debug("resources released",
 		"node_id", nodeID,
 		"task_id", task.ID,
 		"used_cpu", node.UsedCPU,
 		"used_memory", node.UsedMemory,
+		"used_disk", node.UsedDisk,
 		"task_count", node.TaskCount)
 
 	return nil
@@ -652,6 +944,48 @@ func (s *SimpleScheduler) stopHeartbeatWorker(nodeID string) {
 	}
 }
 
+// NodeHeartbeat -> heartbeat from node agent, replaces simulated heartbeat worker
+func (s *SimpleScheduler) NodeHeartbeat(ctx context.Context, nodeID string, report *types.NodeReport) error {
+	s.mu.RLock()
+	node, exists := s.nodes[nodeID]
+	s.mu.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("node with ID %s not found", nodeID)
+	}
+
+	s.stopHeartbeatWorker(nodeID)
+
+	node.Mu.Lock()
+	defer node.Mu.Unlock()
+
+	if !node.Agent {
+		s.logger.Info("node agent connected", "node_id", nodeID)
+	}
+	node.Agent = true
+	node.Report = report
+	node.LastSeen = time.Now()
+
+	// Draining is set by user, only lost node comes back
+	if node.Status == types.NodeStatusNotReady {
+		setNodeStatus(node, types.NodeStatusReady)
+		s.logger.Info("node is back, marked ready", "node_id", nodeID)
+	}
+	return nil
+}
+
+// setNodeStatus -> status with NotReady start time (node.Mu is held)
+func setNodeStatus(node *types.Node, status types.NodeStatus) {
+	switch {
+	case status == types.NodeStatusNotReady && node.Status != types.NodeStatusNotReady:
+		now := time.Now()
+		node.NotReadySince = &now
+	case status != types.NodeStatusNotReady:
+		node.NotReadySince = nil
+	}
+	node.Status = status
+}
+
 // Send heartbeat signal
 func (s *SimpleScheduler) sendHeartbeat(nodeID string) {
 	s.mu.RLock()
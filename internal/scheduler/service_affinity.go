@@ -0,0 +1,150 @@
+// Package scheduler. service_affinity.go -> правила размещения относительно
+// задач других сервисов (co-location / separation) по ключу топологии.
+package scheduler
+
+import (
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// placement -> snapshot of active tasks by node
+type placement struct {
+	nodes map[string]*types.Node   // nodeID -> node
+	tasks map[string][]*types.Task // nodeID -> active tasks
+}
+
+// currentPlacement -> active tasks on all known nodes (from TaskStore.ListByNodeID)
+func (s *SimpleScheduler) currentPlacement(excludeTaskID string) *placement {
+	s.mu.RLock()
+	nodes := make(map[string]*types.Node, len(s.nodes))
+	for id, node := range s.nodes {
+		nodes[id] = node
+	}
+	s.mu.RUnlock()
+
+	p := &placement{
+		nodes: nodes,
+		tasks: make(map[string][]*types.Task, len(nodes)),
+	}
+
+	for nodeID := range nodes {
+		for _, t := range s.getNodeTasks(nodeID) {
+			if t.ID != excludeTaskID && t.IsActive() {
+				p.tasks[nodeID] = append(p.tasks[nodeID], t)
+			}
+		}
+	}
+	return p
+}
+
+// placementFor -> placement snapshot only if task has service rules or topology spread
+func (s *SimpleScheduler) placementFor(task *types.Task) *placement {
+	if task.ServiceConfig == nil {
+		return nil
+	}
+	if !task.ServiceConfig.SchedulingConstraints.HasServiceRules() && len(task.ServiceConfig.TopologySpread) == 0 {
+		return nil
+	}
+	return s.currentPlacement(task.ID)
+}
+
+// domainCounts -> count of service tasks by topology domain
+func (p *placement) domainCounts(serviceName, topologyKey string) map[string]int {
+	counts := make(map[string]int)
+	for nodeID, tasks := range p.tasks {
+		node, exists := p.nodes[nodeID]
+		if !exists {
+			continue
+		}
+		domain, ok := node.TopologyValue(topologyKey)
+		if !ok {
+			continue
+		}
+		for _, t := range tasks {
+			if t.ServiceName == serviceName {
+				counts[domain]++
+			}
+		}
+	}
+	return counts
+}
+
+// filterServiceAffinity -> only nodes satisfying required service (anti-)affinity rules
+func (s *SimpleScheduler) filterServiceAffinity(nodes []*types.Node, task *types.Task, p *placement) []*types.Node {
+	constraints := task.ServiceConfig.SchedulingConstraints
+	if !constraints.HasServiceRules() {
+		return nodes
+	}
+
+	result := make([]*types.Node, 0, len(nodes))
+	for _, node := range nodes {
+		if s.nodeMatchesServiceRules(node, task, constraints, p) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// nodeMatchesServiceRules -> check required rules for one node
+func (s *SimpleScheduler) nodeMatchesServiceRules(node *types.Node, task *types.Task, constraints *types.SchedulingConstraints, p *placement) bool {
+	for _, rule := range constraints.ServiceAffinity {
+		if rule.IsPreferred() {
+			continue
+		}
+
+		counts := p.domainCounts(rule.Service, rule.TopologyKey)
+
+		// First replica of self-affinity has nothing to join
+		if rule.Service == task.ServiceName && len(counts) == 0 {
+			continue
+		}
+
+		domain, ok := node.TopologyValue(rule.TopologyKey)
+		if !ok || counts[domain] == 0 {
+			return false
+		}
+	}
+
+	for _, rule := range constraints.ServiceAntiAffinity {
+		if rule.IsPreferred() {
+			continue
+		}
+
+		domain, ok := node.TopologyValue(rule.TopologyKey)
+		if !ok {
+			continue
+		}
+		if p.domainCounts(rule.Service, rule.TopologyKey)[domain] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// serviceAffinityScore -> weight of matched preferred service rules for the node
+func (s *SimpleScheduler) serviceAffinityScore(node *types.Node, task *types.Task, p *placement) int {
+	if p == nil || task.ServiceConfig == nil {
+		return 0
+	}
+	constraints := task.ServiceConfig.SchedulingConstraints
+
+	score := 0
+	for _, rule := range constraints.ServiceAffinity {
+		if !rule.IsPreferred() {
+			continue
+		}
+		if domain, ok := node.TopologyValue(rule.TopologyKey); ok &&
+			p.domainCounts(rule.Service, rule.TopologyKey)[domain] > 0 {
+			score += rule.Weight
+		}
+	}
+	for _, rule := range constraints.ServiceAntiAffinity {
+		if !rule.IsPreferred() {
+			continue
+		}
+		if domain, ok := node.TopologyValue(rule.TopologyKey); ok &&
+			p.domainCounts(rule.Service, rule.TopologyKey)[domain] > 0 {
+			score -= rule.Weight
+		}
+	}
+	return score
+}
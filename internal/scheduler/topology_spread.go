@@ -0,0 +1,93 @@
+// Package scheduler. topology_spread.go -> равномерное распределение реплик
+// сервиса по доменам топологии (зоны, узлы, любые метки) с ограничением max_skew.
+package scheduler
+
+import (
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// spreadCounts -> service replicas by domain, domains taken from eligible nodes
+func (p *placement) spreadCounts(nodes []*types.Node, serviceName, topologyKey string) map[string]int {
+	counts := make(map[string]int)
+	for _, node := range nodes {
+		if domain, ok := node.TopologyValue(topologyKey); ok {
+			counts[domain] = 0
+		}
+	}
+	for domain, count := range p.domainCounts(serviceName, topologyKey) {
+		if _, eligible := counts[domain]; eligible {
+			counts[domain] = count
+		}
+	}
+	return counts
+}
+
+// filterTopologySpread -> drop nodes breaking DoNotSchedule constraints
+func (s *SimpleScheduler) filterTopologySpread(nodes []*types.Node, task *types.Task, p *placement) []*types.Node {
+	result := nodes
+	for _, constraint := range task.ServiceConfig.TopologySpread {
+		if constraint.WhenUnsatisfiable == types.ScheduleAnyway {
+			continue
+		}
+
+		counts := p.spreadCounts(nodes, task.ServiceName, constraint.TopologyKey)
+		minCount := minDomainCount(counts)
+
+		filtered := make([]*types.Node, 0, len(result))
+		for _, node := range result {
+			domain, ok := node.TopologyValue(constraint.TopologyKey)
+			if !ok {
+				continue
+			}
+			if counts[domain]+1-minCount <= constraint.MaxSkew {
+				filtered = append(filtered, node)
+			}
+		}
+		result = filtered
+	}
+	return result
+}
+
+// preferTopologySpread -> keep nodes in least loaded domains for ScheduleAnyway constraints
+func (s *SimpleScheduler) preferTopologySpread(nodes []*types.Node, task *types.Task, p *placement) []*types.Node {
+	result := nodes
+	for _, constraint := range task.ServiceConfig.TopologySpread {
+		if constraint.WhenUnsatisfiable != types.ScheduleAnyway {
+			continue
+		}
+
+		counts := p.spreadCounts(result, task.ServiceName, constraint.TopologyKey)
+		if len(counts) == 0 {
+			continue
+		}
+		minCount := minDomainCount(counts)
+
+		filtered := make([]*types.Node, 0, len(result))
+		for _, node := range result {
+			if domain, ok := node.TopologyValue(constraint.TopologyKey); ok && counts[domain] == minCount {
+				filtered = append(filtered, node)
+			}
+		}
+
+		// Soft rule never leaves task without nodes
+		if len(filtered) > 0 {
+			result = filtered
+		}
+	}
+	return result
+}
+
+// ========= HELPERS =========
+
+func minDomainCount(counts map[string]int) int {
+	minCount := -1
+	for _, count := range counts {
+		if minCount < 0 || count < minCount {
+			minCount = count
+		}
+	}
+	if minCount < 0 {
+		return 0
+	}
+	return minCount
+}
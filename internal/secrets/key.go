@@ -0,0 +1,71 @@
+// Package secrets. key.go -> мастер-ключ: из переменной окружения или файла,
+// при отсутствии файла генерируется новый ключ.
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// KeySize -> AES-256
+const KeySize = 32
+
+// LoadKey -> master key from config, generated into key_file when neither is present (true)
+func LoadKey(cfg types.SecretsConfig) ([]byte, bool, error) {
+	const op = "secrets.LoadKey"
+
+	if cfg.Key != "" {
+		key, err := decodeKey(cfg.Key)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: GORCHESTER_SECRETS_KEY: %w", op, err)
+		}
+		return key, false, nil
+	}
+
+	data, err := os.ReadFile(cfg.KeyFile)
+	if err == nil {
+		key, err := decodeKey(string(data))
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %s: %w", op, cfg.KeyFile, err)
+		}
+		return key, false, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.KeyFile), 0o700); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+	// O_EXCL -> never overwrite key another process just wrote
+	f, err := os.OpenFile(cfg.KeyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: failed to write generated key: %w", op, err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		return nil, false, fmt.Errorf("%s: failed to write generated key: %w", op, err)
+	}
+	return key, true, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("key must be base64: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
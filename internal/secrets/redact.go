@@ -0,0 +1,116 @@
+// Package secrets. redact.go -> значения секретов (текущие и прежние версии)
+// вырезаются из логов и ответов API.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+)
+
+// Redacted -> replacement of secret value
+const Redacted = "[REDACTED]"
+
+// minRedactLen -> shorter values would mangle unrelated output
+const minRedactLen = 4
+
+// addRedaction -> value and its JSON escaped form
+func (s *Store) addRedaction(value []byte) {
+	if len(value) < minRedactLen {
+		return
+	}
+	forms := []string{string(value)}
+	if quoted, err := json.Marshal(string(value)); err == nil {
+		if escaped := string(quoted[1 : len(quoted)-1]); escaped != forms[0] {
+			forms = append(forms, escaped)
+		}
+	}
+
+	s.redactMu.Lock()
+	defer s.redactMu.Unlock()
+	for _, form := range forms {
+		if !containsString(s.redact, form) {
+			s.redact = append(s.redact, form)
+		}
+	}
+}
+
+// Redact -> text with secret values replaced
+func (s *Store) Redact(text string) string {
+	s.redactMu.RLock()
+	defer s.redactMu.RUnlock()
+	for _, value := range s.redact {
+		if strings.Contains(text, value) {
+			text = strings.ReplaceAll(text, value, Redacted)
+		}
+	}
+	return text
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHandler -> slog handler hiding secret values in message and attributes
+type redactHandler struct {
+	next  slog.Handler
+	store *Store
+}
+
+// NewRedactHandler -> wrap handler of logger
+func NewRedactHandler(next slog.Handler, store *Store) slog.Handler {
+	return &redactHandler{next: next, store: store}
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	clean := slog.NewRecord(r.Time, r.Level, h.store.Redact(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		clean.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, clean)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		clean[i] = h.redactAttr(a)
+	}
+	return &redactHandler{next: h.next.WithAttrs(clean), store: h.store}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{next: h.next.WithGroup(name), store: h.store}
+}
+
+// redactAttr -> strings, errors and groups, other kinds can't hold secret text
+func (h *redactHandler) redactAttr(a slog.Attr) slog.Attr {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, h.store.Redact(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		clean := make([]any, len(group))
+		for i, ga := range group {
+			clean[i] = h.redactAttr(ga)
+		}
+		return slog.Group(a.Key, clean...)
+	case slog.KindAny:
+		text := v.String()
+		if redacted := h.store.Redact(text); redacted != text {
+			return slog.String(a.Key, redacted)
+		}
+	}
+	return a
+}
@@ -0,0 +1,244 @@
+// Package secrets. Хранилище секретов, зашифрованных мастер-ключом (AES-256-GCM).
+// store.go -> секрет хранится файлом <data_dir>/secrets/<name>.json, значение в памяти
+// не держится: расшифровывается только при запуске задачи.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+var (
+	ErrNotFound = errors.New("secret not found")
+	ErrExists   = errors.New("secret already exists")
+)
+
+// MaxValueSize -> secrets are small (keys, passwords, certificates)
+const MaxValueSize = 512 * 1024
+
+// record -> secret file on disk
+type record struct {
+	Name       string    `json:"name"`
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+}
+
+// Store -> encrypted secrets in directory
+type Store struct {
+	dir  string
+	aead cipher.AEAD
+
+	mu      sync.RWMutex
+	records map[string]*record
+
+	redactMu sync.RWMutex
+	redact   []string // current and previous values (and their JSON form) hidden in output
+}
+
+// Open -> store in dir, existing secrets are decrypted once to check key
+func Open(dir string, key []byte) (*Store, error) {
+	const op = "secrets.Open"
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid master key: %w", op, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("%s: failed to create %s: %w", op, dir, err)
+	}
+
+	s := &Store{dir: dir, aead: aead, records: make(map[string]*record)}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("%s: corrupted secret file %s: %w", op, file, err)
+		}
+		// Wrong key is an error at start, not when task needs secret
+		value, err := s.decrypt(&rec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to decrypt secret %s (wrong master key?): %w", op, rec.Name, err)
+		}
+		s.records[rec.Name] = &rec
+		s.addRedaction(value)
+	}
+	return s, nil
+}
+
+// List -> metadata of all secrets by name
+func (s *Store) List() []types.Secret {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]types.Secret, 0, len(s.records))
+	for _, rec := range s.records {
+		result = append(result, metadata(rec))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Get -> metadata of secret
+func (s *Store) Get(name string) (types.Secret, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.records[name]
+	if !ok {
+		return types.Secret{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return metadata(rec), nil
+}
+
+// Create -> new secret with version 1
+func (s *Store) Create(name string, value []byte) (types.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[name]; ok {
+		return types.Secret{}, fmt.Errorf("%w: %s", ErrExists, name)
+	}
+	now := time.Now()
+	rec := &record{Name: name, Version: 1, CreatedAt: now, UpdatedAt: now}
+	if err := s.write(rec, value); err != nil {
+		return types.Secret{}, err
+	}
+	s.records[name] = rec
+	return metadata(rec), nil
+}
+
+// Rotate -> new value of existing secret, version is incremented
+func (s *Store) Rotate(name string, value []byte) (types.Secret, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.records[name]
+	if !ok {
+		return types.Secret{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	rec := &record{Name: name, Version: old.Version + 1, CreatedAt: old.CreatedAt, UpdatedAt: time.Now()}
+	if err := s.write(rec, value); err != nil {
+		return types.Secret{}, err
+	}
+	s.records[name] = rec
+	return metadata(rec), nil
+}
+
+// Delete -> remove secret file
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err := os.Remove(s.path(name)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("secrets: failed to remove %s: %w", name, err)
+	}
+	delete(s.records, name)
+	return nil
+}
+
+// Value -> decrypted value and version, only for delivery to container
+func (s *Store) Value(name string) ([]byte, int, error) {
+	s.mu.RLock()
+	rec, ok := s.records[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	value, err := s.decrypt(rec)
+	if err != nil {
+		return nil, 0, fmt.Errorf("secrets: failed to decrypt %s: %w", name, err)
+	}
+	return value, rec.Version, nil
+}
+
+// write -> encrypt value into file of record, replaced atomically
+func (s *Store) write(rec *record, value []byte) error {
+	const op = "secrets.write"
+
+	rec.Nonce = make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(rec.Nonce); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	rec.Ciphertext = s.aead.Seal(nil, rec.Nonce, value, additionalData(rec))
+
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	tmp := s.path(rec.Name) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if err := os.Rename(tmp, s.path(rec.Name)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	s.addRedaction(value)
+	return nil
+}
+
+func (s *Store) decrypt(rec *record) ([]byte, error) {
+	return s.aead.Open(nil, rec.Nonce, rec.Ciphertext, additionalData(rec))
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// additionalData -> ciphertext can't be moved to other secret or version
+func additionalData(rec *record) []byte {
+	return []byte(rec.Name + "\x00" + strconv.Itoa(rec.Version))
+}
+
+func metadata(rec *record) types.Secret {
+	return types.Secret{
+		Name:      rec.Name,
+		Version:   rec.Version,
+		CreatedAt: rec.CreatedAt,
+		UpdatedAt: rec.UpdatedAt,
+	}
+}
+
+// ValidName -> same rule as volume names in config, usable as file name in DataDir
+func ValidName(name string) bool {
+	if name == "" || len(name) > 128 {
+		return false
+	}
+	for i, r := range name {
+		alnum := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+		if !alnum && (i == 0 || r != '_' && r != '.' && r != '-') {
+			return false
+		}
+	}
+	return true
+}
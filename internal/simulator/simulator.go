@@ -0,0 +1,194 @@
+// Package simulator. Симуляция размещения конфигурации на кластере без запуска контейнеров.
+// Используется настоящий SimpleScheduler, а вместо Docker -> фейковый runtime,
+// который сразу помечает задачи запущенными в TaskStore.
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+
+	"github.com/exitae337/gorchester/internal/scheduler"
+	"github.com/exitae337/gorchester/internal/store"
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// Placement -> replica of service placed on node
+type Placement struct {
+	TaskID  string `json:"task_id"`
+	Service string `json:"service"`
+	NodeID  string `json:"node_id"`
+}
+
+// NodeUsage -> requested resources on node after placement
+type NodeUsage struct {
+	NodeID      string  `json:"node_id"`
+	Tasks       int     `json:"tasks"`
+	CPUUsed     int64   `json:"cpu_used"`
+	CPUCapacity int64   `json:"cpu_capacity"`
+	MemUsed     int64   `json:"mem_used"`
+	MemCapacity int64   `json:"mem_capacity"`
+	CPUPercent  float64 `json:"cpu_percent"`
+	MemPercent  float64 `json:"mem_percent"`
+}
+
+// Unschedulable -> replica without node
+type Unschedulable struct {
+	Service string `json:"service"`
+	Replica int    `json:"replica"`
+	Reason  string `json:"reason"`
+}
+
+// Violation -> placement breaks a rule (soft -> preferred rule)
+type Violation struct {
+	Service string `json:"service"`
+	TaskID  string `json:"task_id,omitempty"`
+	NodeID  string `json:"node_id,omitempty"`
+	Rule    string `json:"rule"`
+	Soft    bool   `json:"soft"`
+}
+
+// Report -> result of one simulation run
+type Report struct {
+	Strategy      scheduler.Strategy `json:"strategy"`
+	Placements    []Placement        `json:"placements"`
+	Nodes         []NodeUsage        `json:"nodes"`
+	Unschedulable []Unschedulable    `json:"unschedulable"`
+	Violations    []Violation        `json:"violations"`
+}
+
+// NodesUsed -> nodes with at least one task
+func (r *Report) NodesUsed() int {
+	used := 0
+	for _, n := range r.Nodes {
+		if n.Tasks > 0 {
+			used++
+		}
+	}
+	return used
+}
+
+// MaxUtilization -> highest CPU and memory percent among nodes
+func (r *Report) MaxUtilization() (float64, float64) {
+	var maxCPU, maxMem float64
+	for _, n := range r.Nodes {
+		if n.CPUPercent > maxCPU {
+			maxCPU = n.CPUPercent
+		}
+		if n.MemPercent > maxMem {
+			maxMem = n.MemPercent
+		}
+	}
+	return maxCPU, maxMem
+}
+
+// Run -> place all services of config with the strategy
+func Run(ctx context.Context, cfg *types.OchestratorConfig, strategy scheduler.Strategy) (*Report, error) {
+	taskStore := store.New()
+
+	schedulerConfig := scheduler.DefaultConfig()
+	schedulerConfig.Strategy = strategy
+	schedulerConfig.PortRangeStart = cfg.HostPortRange.Start
+	schedulerConfig.PortRangeEnd = cfg.HostPortRange.End
+	schedulerConfig.Overcommit = cfg.Overcommit
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	sched := scheduler.New(schedulerConfig, logger, cfg.Nodes, taskStore)
+	defer sched.Stop()
+
+	nodes, err := sched.GetNodes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nodes: %w", err)
+	}
+
+	report := &Report{
+		Strategy:      strategy,
+		Placements:    make([]Placement, 0),
+		Unschedulable: make([]Unschedulable, 0),
+		Violations:    make([]Violation, 0),
+	}
+
+	// Same order as orchestrator init: services from config, replicas one by one
+	for i := range cfg.Services {
+		svc := &cfg.Services[i]
+
+		replicas := svc.Replicas
+		if svc.ServiceType == types.ServiceTypeDaemon {
+			replicas = svc.Replicas * len(nodes)
+		}
+
+		for replica := 0; replica < replicas; replica++ {
+			task := &types.Task{
+				ID:            fmt.Sprintf("%s-%d", svc.ServiceName, replica),
+				ServiceName:   svc.ServiceName,
+				ServiceConfig: svc,
+			}
+
+			nodeID, err := sched.SelectNode(ctx, task, nodes)
+			if err != nil {
+				report.Unschedulable = append(report.Unschedulable, Unschedulable{
+					Service: svc.ServiceName,
+					Replica: replica,
+					Reason:  err.Error(),
+				})
+				continue
+			}
+
+			// Fake runtime -> container is running right away
+			task.NodeID = nodeID
+			task.Status = types.TaskStatusRunning
+			task.DesiredState = types.TaskStatusRunning
+			if err := taskStore.Create(ctx, task); err != nil {
+				return nil, fmt.Errorf("failed to save simulated task: %w", err)
+			}
+
+			report.Placements = append(report.Placements, Placement{
+				TaskID:  task.ID,
+				Service: svc.ServiceName,
+				NodeID:  nodeID,
+			})
+		}
+	}
+
+	tasks, err := taskStore.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list simulated tasks: %w", err)
+	}
+
+	report.Nodes = nodeUsage(nodes)
+	report.Violations = findViolations(cfg, nodes, tasks)
+
+	return report, nil
+}
+
+// nodeUsage -> utilization of every node, sorted by node ID
+func nodeUsage(nodes []*types.Node) []NodeUsage {
+	result := make([]NodeUsage, 0, len(nodes))
+	for _, node := range nodes {
+		node.Mu.RLock()
+		usage := NodeUsage{
+			NodeID:      node.ID,
+			Tasks:       node.TaskCount,
+			CPUUsed:     node.UsedCPU,
+			CPUCapacity: node.Resources.CPU,
+			MemUsed:     node.UsedMemory,
+			MemCapacity: node.Resources.Memory,
+		}
+		node.Mu.RUnlock()
+
+		if usage.CPUCapacity > 0 {
+			usage.CPUPercent = float64(usage.CPUUsed) / float64(usage.CPUCapacity) * 100
+		}
+		if usage.MemCapacity > 0 {
+			usage.MemPercent = float64(usage.MemUsed) / float64(usage.MemCapacity) * 100
+		}
+		result = append(result, usage)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NodeID < result[j].NodeID
+	})
+	return result
+}
@@ -0,0 +1,203 @@
+// Package simulator. violations.go -> проверка итогового размещения:
+// affinity по меткам, правила между сервисами и topology spread.
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// findViolations -> all rules broken by final placement
+func findViolations(cfg *types.OchestratorConfig, nodes []*types.Node, tasks []*types.Task) []Violation {
+	nodeByID := make(map[string]*types.Node, len(nodes))
+	for _, node := range nodes {
+		nodeByID[node.ID] = node
+	}
+
+	result := make([]Violation, 0)
+	for i := range cfg.Services {
+		svc := &cfg.Services[i]
+		result = append(result, labelViolations(svc, nodes, nodeByID, tasks)...)
+		result = append(result, serviceRuleViolations(svc, nodeByID, tasks)...)
+		result = append(result, spreadViolations(svc, nodes, nodeByID, tasks)...)
+	}
+	return result
+}
+
+// labelViolations -> required label rules and replicas not on the best preferred nodes
+func labelViolations(svc *types.ServiceConfig, nodes []*types.Node, nodeByID map[string]*types.Node, tasks []*types.Task) []Violation {
+	constraints := svc.SchedulingConstraints
+	result := make([]Violation, 0)
+
+	bestScore := 0
+	for _, node := range nodes {
+		node.Mu.RLock()
+		if constraints.MatchesRequired(node.Labels) {
+			if score := constraints.PreferenceScore(node.Labels); score > bestScore {
+				bestScore = score
+			}
+		}
+		node.Mu.RUnlock()
+	}
+
+	for _, task := range tasks {
+		node, exists := nodeByID[task.NodeID]
+		if task.ServiceName != svc.ServiceName || !exists {
+			continue
+		}
+
+		node.Mu.RLock()
+		matches := constraints.MatchesRequired(node.Labels)
+		score := constraints.PreferenceScore(node.Labels)
+		node.Mu.RUnlock()
+
+		if !matches {
+			result = append(result, Violation{
+				Service: svc.ServiceName,
+				TaskID:  task.ID,
+				NodeID:  node.ID,
+				Rule:    "required affinity",
+			})
+		}
+		if score < bestScore {
+			result = append(result, Violation{
+				Service: svc.ServiceName,
+				TaskID:  task.ID,
+				NodeID:  node.ID,
+				Rule:    fmt.Sprintf("preferred affinity score %d, best node has %d", score, bestScore),
+				Soft:    true,
+			})
+		}
+	}
+	return result
+}
+
+// serviceRuleViolations -> affinity / anti-affinity between services in topology domains
+func serviceRuleViolations(svc *types.ServiceConfig, nodeByID map[string]*types.Node, tasks []*types.Task) []Violation {
+	if !svc.SchedulingConstraints.HasServiceRules() {
+		return nil
+	}
+
+	// peersInDomain -> tasks of service in the same domain as task (task itself excluded)
+	peersInDomain := func(task *types.Task, service, key string) (int, bool) {
+		domain, ok := nodeByID[task.NodeID].TopologyValue(key)
+		if !ok {
+			return 0, false
+		}
+		count := 0
+		for _, other := range tasks {
+			if other.ID == task.ID || other.ServiceName != service {
+				continue
+			}
+			if node, exists := nodeByID[other.NodeID]; exists {
+				if value, ok := node.TopologyValue(key); ok && value == domain {
+					count++
+				}
+			}
+		}
+		return count, true
+	}
+
+	result := make([]Violation, 0)
+	for _, task := range tasks {
+		if task.ServiceName != svc.ServiceName {
+			continue
+		}
+		if _, exists := nodeByID[task.NodeID]; !exists {
+			continue
+		}
+
+		for _, rule := range svc.SchedulingConstraints.ServiceAffinity {
+			peers, ok := peersInDomain(task, rule.Service, rule.TopologyKey)
+			// Self-affinity of a single replica is satisfied
+			if rule.Service == svc.ServiceName && ok && countService(tasks, svc.ServiceName) == 1 {
+				continue
+			}
+			if !ok || peers == 0 {
+				result = append(result, Violation{
+					Service: svc.ServiceName,
+					TaskID:  task.ID,
+					NodeID:  task.NodeID,
+					Rule:    fmt.Sprintf("service affinity to %s by %s", rule.Service, rule.TopologyKey),
+					Soft:    rule.IsPreferred(),
+				})
+			}
+		}
+
+		for _, rule := range svc.SchedulingConstraints.ServiceAntiAffinity {
+			if peers, ok := peersInDomain(task, rule.Service, rule.TopologyKey); ok && peers > 0 {
+				result = append(result, Violation{
+					Service: svc.ServiceName,
+					TaskID:  task.ID,
+					NodeID:  task.NodeID,
+					Rule:    fmt.Sprintf("service anti-affinity to %s by %s", rule.Service, rule.TopologyKey),
+					Soft:    rule.IsPreferred(),
+				})
+			}
+		}
+	}
+	return result
+}
+
+// spreadViolations -> domains skew above max_skew
+func spreadViolations(svc *types.ServiceConfig, nodes []*types.Node, nodeByID map[string]*types.Node, tasks []*types.Task) []Violation {
+	result := make([]Violation, 0)
+	for _, constraint := range svc.TopologySpread {
+		counts := make(map[string]int)
+		for _, node := range nodes {
+			node.Mu.RLock()
+			eligible := svc.SchedulingConstraints.MatchesRequired(node.Labels)
+			node.Mu.RUnlock()
+			if !eligible {
+				continue
+			}
+			if domain, ok := node.TopologyValue(constraint.TopologyKey); ok {
+				counts[domain] += 0
+			}
+		}
+		for _, task := range tasks {
+			node, exists := nodeByID[task.NodeID]
+			if task.ServiceName != svc.ServiceName || !exists {
+				continue
+			}
+			if domain, ok := node.TopologyValue(constraint.TopologyKey); ok {
+				counts[domain]++
+			}
+		}
+		if len(counts) == 0 {
+			continue
+		}
+
+		minCount, maxCount := -1, 0
+		for _, count := range counts {
+			if minCount < 0 || count < minCount {
+				minCount = count
+			}
+			if count > maxCount {
+				maxCount = count
+			}
+		}
+
+		if maxCount-minCount > constraint.MaxSkew {
+			result = append(result, Violation{
+				Service: svc.ServiceName,
+				Rule: fmt.Sprintf("topology spread by %s: skew %d > max_skew %d",
+					constraint.TopologyKey, maxCount-minCount, constraint.MaxSkew),
+				Soft: constraint.WhenUnsatisfiable == types.ScheduleAnyway,
+			})
+		}
+	}
+	return result
+}
+
+// countService -> placed replicas of service
+func countService(tasks []*types.Task, serviceName string) int {
+	count := 0
+	for _, task := range tasks {
+		if task.ServiceName == serviceName {
+			count++
+		}
+	}
+	return count
+}
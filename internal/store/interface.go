@@ -45,3 +45,19 @@ type TaskStore interface {
 	// Increment Restart Counter
 	IncrementRestartCounter(ctx context.Context, id string) error
 }
+
+// VolumeStore defines the interface for tracked volumes
+type VolumeStore interface {
+	// Create new Volume record
+	Create(ctx context.Context, volume *types.Volume) error
+	// Get Volume by name
+	Get(ctx context.Context, name string) (*types.Volume, error)
+	// Update Volume (node binding, status)
+	Update(ctx context.Context, volume *types.Volume) error
+	// Delete Volume record
+	Delete(ctx context.Context, name string) error
+	// List all Volumes
+	List(ctx context.Context) ([]*types.Volume, error)
+	// List claim Volumes of service
+	ListByService(ctx context.Context, serviceName string) ([]*types.Volume, error)
+}
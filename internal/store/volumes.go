@@ -0,0 +1,119 @@
+// Package store. volumes.go -> хранилище записей о томах в оперативной памяти:
+// том, его привязка к узлу и владелец (сервис и ordinal для томов реплик).
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/exitae337/gorchester/internal/types"
+)
+
+// In-memory struct -> store for Volumes
+type MemoryVolumeStore struct {
+	mu      sync.RWMutex
+	volumes map[string]*types.Volume // name -> volume
+}
+
+var _ VolumeStore = (*MemoryVolumeStore)(nil)
+
+// NewVolumeStore -> Constructor
+func NewVolumeStore() *MemoryVolumeStore {
+	return &MemoryVolumeStore{
+		volumes: make(map[string]*types.Volume),
+	}
+}
+
+// Create -> save New Volume in store
+func (mem *MemoryVolumeStore) Create(ctx context.Context, volume *types.Volume) error {
+	if volume == nil {
+		return fmt.Errorf("volume can't be nil")
+	}
+	if volume.Name == "" {
+		return fmt.Errorf("volume name can't be empty")
+	}
+
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+
+	if _, exists := mem.volumes[volume.Name]; exists {
+		return fmt.Errorf("volume %s already exists", volume.Name)
+	}
+	if volume.CreatedAt.IsZero() {
+		volume.CreatedAt = time.Now()
+	}
+
+	mem.volumes[volume.Name] = volume.DeepCopy()
+	return nil
+}
+
+// Get -> get Volume by name
+func (mem *MemoryVolumeStore) Get(ctx context.Context, name string) (*types.Volume, error) {
+	mem.mu.RLock()
+	defer mem.mu.RUnlock()
+
+	volume, exists := mem.volumes[name]
+	if !exists {
+		return nil, fmt.Errorf("volume %s is not exists", name)
+	}
+	return volume.DeepCopy(), nil
+}
+
+// Update -> replace Volume record
+func (mem *MemoryVolumeStore) Update(ctx context.Context, volume *types.Volume) error {
+	if volume == nil {
+		return fmt.Errorf("volume can't be nil for updating")
+	}
+
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+
+	if _, exists := mem.volumes[volume.Name]; !exists {
+		return fmt.Errorf("volume %s is not exists", volume.Name)
+	}
+	mem.volumes[volume.Name] = volume.DeepCopy()
+	return nil
+}
+
+// Delete Volume from Store
+func (mem *MemoryVolumeStore) Delete(ctx context.Context, name string) error {
+	mem.mu.Lock()
+	defer mem.mu.Unlock()
+
+	if _, exists := mem.volumes[name]; !exists {
+		return fmt.Errorf("volume %s is not exists", name)
+	}
+	delete(mem.volumes, name)
+	return nil
+}
+
+// List -> all Volumes sorted by name
+func (mem *MemoryVolumeStore) List(ctx context.Context) ([]*types.Volume, error) {
+	mem.mu.RLock()
+	defer mem.mu.RUnlock()
+
+	result := make([]*types.Volume, 0, len(mem.volumes))
+	for _, volume := range mem.volumes {
+		result = append(result, volume.DeepCopy())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// ListByService -> claim Volumes of service
+func (mem *MemoryVolumeStore) ListByService(ctx context.Context, serviceName string) ([]*types.Volume, error) {
+	mem.mu.RLock()
+	defer mem.mu.RUnlock()
+
+	result := make([]*types.Volume, 0)
+	for _, volume := range mem.volumes {
+		if volume.ServiceName == serviceName {
+			result = append(result, volume.DeepCopy())
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
@@ -0,0 +1,93 @@
+// Package types. cpuset.go -> разбор и форматирование списков ядер
+// в формате Docker cpuset ("0-3,6,8-9") и NUMA-топология узла.
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NUMANode -> group of cores sharing memory controller
+type NUMANode struct {
+	ID    int    `yaml:"id" json:"id"`
+	Cores string `yaml:"cores" json:"cores"` // Example: "0-7", "0,2,4,6"
+}
+
+// ParseCPUSet -> "0-3,6" -> [0 1 2 3 6]
+func ParseCPUSet(cpuset string) ([]int, error) {
+	cpuset = strings.TrimSpace(cpuset)
+	if cpuset == "" {
+		return nil, nil
+	}
+
+	seen := make(map[int]bool)
+	result := make([]int, 0)
+	for _, part := range strings.Split(cpuset, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty element in cpuset %q", cpuset)
+		}
+
+		first, last := part, part
+		if idx := strings.Index(part, "-"); idx >= 0 {
+			first, last = part[:idx], part[idx+1:]
+		}
+
+		from, err := strconv.Atoi(first)
+		if err != nil || from < 0 {
+			return nil, fmt.Errorf("invalid core %q in cpuset %q", first, cpuset)
+		}
+		to, err := strconv.Atoi(last)
+		if err != nil || to < from {
+			return nil, fmt.Errorf("invalid core range %q in cpuset %q", part, cpuset)
+		}
+
+		for core := from; core <= to; core++ {
+			if !seen[core] {
+				seen[core] = true
+				result = append(result, core)
+			}
+		}
+	}
+
+	sort.Ints(result)
+	return result, nil
+}
+
+// FormatCPUSet -> [0 1 2 3 6] -> "0-3,6"
+func FormatCPUSet(cores []int) string {
+	if len(cores) == 0 {
+		return ""
+	}
+
+	sorted := make([]int, len(cores))
+	copy(sorted, cores)
+	sort.Ints(sorted)
+
+	parts := make([]string, 0)
+	start, prev := sorted[0], sorted[0]
+	flush := func() {
+		if start == prev {
+			parts = append(parts, strconv.Itoa(start))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", start, prev))
+		}
+	}
+
+	for _, core := range sorted[1:] {
+		if core == prev {
+			continue
+		}
+		if core == prev+1 {
+			prev = core
+			continue
+		}
+		flush()
+		start, prev = core, core
+	}
+	flush()
+
+	return strings.Join(parts, ",")
+}
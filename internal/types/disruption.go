@@ -0,0 +1,76 @@
+// Package types. disruption.go -> бюджет добровольных нарушений сервиса
+// (min_available / max_unavailable числом реплик или процентом).
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DisruptionBudget -> replicas that must stay available during voluntary disruptions
+// (scale down, drain, maintenance, rebalance, eviction after label change), one of two fields
+type DisruptionBudget struct {
+	MinAvailable   IntOrPercent `yaml:"min_available" json:"min_available,omitempty"`     // Healthy replicas kept, "2" or "50%"
+	MaxUnavailable IntOrPercent `yaml:"max_unavailable" json:"max_unavailable,omitempty"` // Replicas missing at once, counting already missing ones
+}
+
+// IntOrPercent -> replica count ("2") or share of desired replicas ("25%"), empty -> not set
+type IntOrPercent string
+
+// UnmarshalJSON -> number or string
+func (v *IntOrPercent) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err == nil {
+		*v = IntOrPercent(n.String())
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("expected number or percentage, got %s", data)
+	}
+	*v = IntOrPercent(s)
+	return nil
+}
+
+// IsSet -> value was given
+func (v IntOrPercent) IsSet() bool {
+	return strings.TrimSpace(string(v)) != ""
+}
+
+// Resolve -> replicas out of total, percentage is rounded up
+func (v IntOrPercent) Resolve(total int) (int, error) {
+	s := strings.TrimSpace(string(v))
+	if pct, isPercent := strings.CutSuffix(s, "%"); isPercent {
+		n, err := strconv.Atoi(pct)
+		if err != nil || n < 0 || n > 100 {
+			return 0, fmt.Errorf("invalid percentage %q, expected 0%%-100%%", s)
+		}
+		return int(math.Ceil(float64(total) * float64(n) / 100)), nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid value %q, expected non-negative number or percentage", s)
+	}
+	return n, nil
+}
+
+// Allowed -> voluntary disruptions allowed with healthy replicas out of desired
+func (b DisruptionBudget) Allowed(desired, healthy int) int {
+	if b.MinAvailable.IsSet() {
+		minAvailable, err := b.MinAvailable.Resolve(desired)
+		if err != nil {
+			return 0
+		}
+		return max(0, healthy-minAvailable)
+	}
+
+	maxUnavailable, err := b.MaxUnavailable.Resolve(desired)
+	if err != nil {
+		return 0
+	}
+	return max(0, maxUnavailable-max(0, desired-healthy))
+}
@@ -0,0 +1,48 @@
+// Package types. ingress.go -> встроенный балансировщик перед репликами сервисов:
+// L4 слушатели по TCP-порту и L7 слушатели с маршрутами по Host и префиксу пути.
+package types
+
+import "time"
+
+// IngressProtocol -> how listener routes connections
+type IngressProtocol string
+
+const (
+	IngressTCP  IngressProtocol = "tcp"  // whole connection goes to one replica
+	IngressHTTP IngressProtocol = "http" // every request is routed by host and path
+)
+
+// BalanceStrategy -> how replica is picked for connection or request
+type BalanceStrategy string
+
+const (
+	BalanceRoundRobin BalanceStrategy = "round_robin"
+	BalanceLeastConn  BalanceStrategy = "least_conn"
+)
+
+// IngressConfig -> proxy in orchestrator process
+type IngressConfig struct {
+	Enabled         bool              `yaml:"enabled" json:"enabled"`
+	DrainTimeout    time.Duration     `yaml:"drain_timeout" json:"drain_timeout"`       // Stopped task keeps open connections this long
+	RefreshInterval time.Duration     `yaml:"refresh_interval" json:"refresh_interval"` // Full refresh of backends besides task events
+	Listeners       []IngressListener `yaml:"listeners" json:"listeners"`
+}
+
+// IngressListener -> one listening port
+type IngressListener struct {
+	Name       string          `yaml:"name" json:"name"`
+	ListenAddr string          `yaml:"listen_addr" json:"listen_addr"`
+	Protocol   IngressProtocol `yaml:"protocol" json:"protocol"` // Default "tcp"
+	Balance    BalanceStrategy `yaml:"balance" json:"balance"`   // Default "round_robin"
+	Service    string          `yaml:"service,omitempty" json:"service,omitempty"`
+	Port       int             `yaml:"port,omitempty" json:"port,omitempty"`     // Container port of service (tcp)
+	Routes     []IngressRoute  `yaml:"routes,omitempty" json:"routes,omitempty"` // http only
+}
+
+// IngressRoute -> requests with matching host and path go to service
+type IngressRoute struct {
+	Host    string `yaml:"host,omitempty" json:"host,omitempty"` // Empty -> any host
+	Path    string `yaml:"path,omitempty" json:"path,omitempty"` // Path prefix, default "/"
+	Service string `yaml:"service" json:"service"`
+	Port    int    `yaml:"port" json:"port"` // Container port of service
+}
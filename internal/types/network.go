@@ -0,0 +1,40 @@
+// Package types. network.go -> управляемые сети Docker: объявления в конфиге,
+// сети задачи с алиасами и неявная сеть сервиса без объявленных сетей (изоляция).
+package types
+
+import "strings"
+
+// DefaultNetworkDriver -> node local network
+const DefaultNetworkDriver = "bridge"
+
+// ImplicitNetworkPrefix -> own network of service without networks, reserved for orchestrator
+const ImplicitNetworkPrefix = "gorchester-"
+
+// NetworkConfig -> network declared at top level of config, created on nodes on first use
+type NetworkConfig struct {
+	Name     string            `yaml:"name" json:"name"`
+	Driver   string            `yaml:"driver" json:"driver"`                     // Default "bridge"
+	Subnet   string            `yaml:"subnet,omitempty" json:"subnet,omitempty"` // CIDR, empty -> chosen by Docker
+	Internal bool              `yaml:"internal" json:"internal"`                 // No traffic outside the network
+	Labels   map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+// TaskNetwork -> network resolved for task, container is reachable by aliases inside it
+type TaskNetwork struct {
+	Name     string            `json:"name"`
+	Driver   string            `json:"driver"`
+	Subnet   string            `json:"subnet,omitempty"`
+	Internal bool              `json:"internal,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Aliases  []string          `json:"aliases,omitempty"`
+}
+
+// ImplicitNetworkName -> network of service that declares no networks
+func ImplicitNetworkName(serviceName string) string {
+	return ImplicitNetworkPrefix + serviceName
+}
+
+// IsImplicitNetwork -> network is owned by one service
+func IsImplicitNetwork(name string) bool {
+	return strings.HasPrefix(name, ImplicitNetworkPrefix)
+}
@@ -24,20 +24,31 @@ const (
 type NodeResources struct {
 	CPU    int64 // in millicores
 	Memory int64 // in bytes
+	Disk   int64 // in bytes, 0 -> not accounted
+
+	Extended map[string]int64 // countable resources: name -> capacity
 }
 
 // Node struct -> node in cluster
 type Node struct {
-	ID        string            `json:"id"`
-	Hostname  string            `json:"hostname"`
-	IP        string            `json:"ip"`
-	Status    NodeStatus        `json:"status"`
-	Labels    map[string]string `json:"labels"`
-	Resources *NodeResources    `json:"resources"`
+	ID         string            `json:"id"`
+	Hostname   string            `json:"hostname"`
+	IP         string            `json:"ip"`
+	Status     NodeStatus        `json:"status"`
+	Labels     map[string]string `json:"labels"`
+	Resources  *NodeResources    `json:"resources"`
+	NUMA       []NUMANode        `json:"numa_nodes,omitempty"` // Core layout, empty -> cores 0..CPU/1000-1
+	Overcommit OvercommitRatios  `json:"overcommit"`           // Capacity multipliers, 0 -> scheduler default
+
+	// No new tasks, running tasks stay (node is leaving or cordoned)
+	Unschedulable bool `json:"unschedulable"`
 
 	// resiurces -> dynamic changes
 	UsedCPU    int64 `json:"used_cpu"`
 	UsedMemory int64 `json:"used_memory"`
+	UsedDisk   int64 `json:"used_disk"`
+
+	UsedExtended map[string]int64 `json:"used_extended,omitempty"`
 
 	// Count of tasks
 	TaskCount int `json:"task_count"`
@@ -45,9 +56,48 @@ type Node struct {
 	// Heartbeat -> last
 	LastSeen time.Time `json:"last_seen"`
 
+	// When node became NotReady, tasks are lost after grace period
+	NotReadySince *time.Time `json:"not_ready_since,omitempty"`
+
+	// Node agent -> heartbeats and usage come from gorchester-agent
+	Agent  bool        `json:"agent"`
+	Report *NodeReport `json:"report,omitempty"`
+
 	Mu sync.RWMutex `json:"-"`
 }
 
+// NodeReport -> real usage and containers reported by node agent
+type NodeReport struct {
+	CPUUsed    int64               `json:"cpu_used"`    // in millicores
+	MemoryUsed int64               `json:"memory_used"` // in bytes
+	DiskUsed   int64               `json:"disk_used"`   // in bytes
+	Containers []ReportedContainer `json:"containers"`
+	ReportedAt time.Time           `json:"reported_at"`
+}
+
+// ReportedContainer -> gorchester container on node
+type ReportedContainer struct {
+	ID     string `json:"id"`
+	TaskID string `json:"task_id"`
+	State  string `json:"state"`
+}
+
+// TopologyKeyNode -> topology domain is the node itself
+const TopologyKeyNode = "node"
+
+// TopologyValue -> node domain for topology key ("node" -> node ID, else label value)
+func (n *Node) TopologyValue(key string) (string, bool) {
+	if key == "" || key == TopologyKeyNode {
+		return n.ID, true
+	}
+
+	n.Mu.RLock()
+	defer n.Mu.RUnlock()
+
+	value, exists := n.Labels[key]
+	return value, exists
+}
+
 // NodeStats
 type NodeStats struct {
 	NodeID       string
@@ -60,10 +110,73 @@ type NodeStats struct {
 
 // NodeConfig
 type NodeConfig struct {
-	ID       string            `yaml:"id" json:"id"`
-	Hostname string            `yaml:"hostname" json:"hostname"`
-	IP       string            `yaml:"ip" json:"ip"`
-	CPU      int64             `yaml:"cpu" json:"cpu"`
-	Memory   int64             `yaml:"memory" json:"memory"`
-	Labels   map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	ID         string            `yaml:"id" json:"id"`
+	Hostname   string            `yaml:"hostname" json:"hostname"`
+	IP         string            `yaml:"ip" json:"ip"`
+	CPU        int64             `yaml:"cpu" json:"cpu"`
+	Memory     int64             `yaml:"memory" json:"memory"`
+	Disk       int64             `yaml:"disk,omitempty" json:"disk,omitempty"`
+	NUMA       []NUMANode        `yaml:"numa_nodes,omitempty" json:"numa_nodes,omitempty"`
+	Extended   map[string]int64  `yaml:"extended_resources,omitempty" json:"extended_resources,omitempty"`
+	Overcommit OvercommitRatios  `yaml:"overcommit,omitempty" json:"overcommit,omitempty"`
+	Labels     map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Docker     DockerEndpoint    `yaml:"docker,omitempty" json:"docker,omitempty"`
+
+	Maintenance []MaintenanceWindow `yaml:"maintenance,omitempty" json:"maintenance,omitempty"`
+}
+
+// NodeUpdate -> runtime change of node, nil fields stay as they are
+type NodeUpdate struct {
+	Labels   map[string]*string `json:"labels,omitempty"`             // null value removes label
+	CPU      *int64             `json:"cpu,omitempty"`                // in millicores
+	Memory   *int64             `json:"memory,omitempty"`             // in bytes
+	Disk     *int64             `json:"disk,omitempty"`               // in bytes, 0 -> not accounted
+	Extended map[string]*int64  `json:"extended_resources,omitempty"` // null value removes resource
+}
+
+// MaintenanceWindow -> recurring node maintenance, node is drained before it and ready after it
+type MaintenanceWindow struct {
+	Schedule    string        `yaml:"schedule" json:"schedule"`                             // cron: "0 3 * * sun", local time of orchestrator
+	Duration    time.Duration `yaml:"duration" json:"duration"`                             // window length
+	DrainBefore time.Duration `yaml:"drain_before,omitempty" json:"drain_before,omitempty"` // drain starts this early, 0 -> drain_timeout
+}
+
+// DockerEndpoint -> Docker daemon of node, empty host -> local daemon (DOCKER_HOST env)
+type DockerEndpoint struct {
+	Host      string `yaml:"host" json:"host"`                         // unix:///var/run/docker.sock, tcp://10.0.0.5:2376, ssh://user@host:22
+	TLSCACert string `yaml:"tls_ca_cert" json:"tls_ca_cert,omitempty"` // tcp + TLS: CA certificate
+	TLSCert   string `yaml:"tls_cert" json:"tls_cert,omitempty"`       // tcp + TLS: client certificate
+	TLSKey    string `yaml:"tls_key" json:"tls_key,omitempty"`         // tcp + TLS: client key
+	SSHKey    string `yaml:"ssh_key" json:"ssh_key,omitempty"`         // ssh: identity file
+}
+
+// NewNode -> node from config, ready and without usage
+func NewNode(nc NodeConfig) *Node {
+	labels := nc.Labels
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	return &Node{
+		ID:       nc.ID,
+		Hostname: nc.Hostname,
+		IP:       nc.IP,
+		Status:   NodeStatusReady,
+		Resources: &NodeResources{
+			CPU:    nc.CPU,
+			Memory: nc.Memory,
+			Disk:   nc.Disk,
+
+			Extended: nc.Extended,
+		},
+		Labels: labels,
+		NUMA:   nc.NUMA,
+
+		Overcommit: nc.Overcommit,
+		LastSeen:   time.Now(),
+	}
+}
+
+// IsRemote -> node has its own Docker endpoint
+func (e DockerEndpoint) IsRemote() bool {
+	return e.Host != ""
 }
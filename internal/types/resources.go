@@ -0,0 +1,98 @@
+// Package types. resources.go -> requests (для планировщика) и limits (для Docker),
+// QoS-классы задач и коэффициенты overcommit узлов.
+package types
+
+// ResourceList -> cpu and memory amounts
+type ResourceList struct {
+	CPUMilliCores int64 `yaml:"cpu_millicores" json:"cpu_millicores"`
+	MemoryBytes   int64 `yaml:"memory_bytes" json:"memory_bytes"`
+}
+
+// QoSClass -> eviction priority of task under node pressure
+type QoSClass string
+
+const (
+	QoSGuaranteed QoSClass = "guaranteed"  // limits == requests, evicted last
+	QoSBurstable  QoSClass = "burstable"   // requests < limits (or no limit)
+	QoSBestEffort QoSClass = "best-effort" // no requests and no limits, evicted first
+)
+
+// OvercommitRatios -> capacity multipliers (1.0 = no overcommit, 0 = inherit)
+type OvercommitRatios struct {
+	CPU    float64 `yaml:"cpu,omitempty" json:"cpu,omitempty"`
+	Memory float64 `yaml:"memory,omitempty" json:"memory,omitempty"`
+}
+
+// CPURequest -> millicores used by scheduler
+func (r ResourceRequirements) CPURequest() int64 {
+	switch {
+	case r.Requests != nil:
+		return r.Requests.CPUMilliCores
+	case r.Limits != nil:
+		return r.Limits.CPUMilliCores
+	default:
+		return r.CPUMilliCores
+	}
+}
+
+// MemoryRequest -> bytes used by scheduler
+func (r ResourceRequirements) MemoryRequest() int64 {
+	switch {
+	case r.Requests != nil:
+		return r.Requests.MemoryBytes
+	case r.Limits != nil:
+		return r.Limits.MemoryBytes
+	default:
+		return r.MemoryBytes
+	}
+}
+
+// CPULimit -> millicores enforced by Docker (0 = unlimited)
+func (r ResourceRequirements) CPULimit() int64 {
+	switch {
+	case r.Limits != nil:
+		return r.Limits.CPUMilliCores
+	case r.Requests != nil:
+		return 0
+	default:
+		return r.CPUMilliCores
+	}
+}
+
+// MemoryLimit -> bytes enforced by Docker (0 = unlimited)
+func (r ResourceRequirements) MemoryLimit() int64 {
+	switch {
+	case r.Limits != nil:
+		return r.Limits.MemoryBytes
+	case r.Requests != nil:
+		return 0
+	default:
+		return r.MemoryBytes
+	}
+}
+
+// QoSClass -> class derived from requests and limits
+func (r ResourceRequirements) QoSClass() QoSClass {
+	reqCPU, reqMem := r.CPURequest(), r.MemoryRequest()
+	limCPU, limMem := r.CPULimit(), r.MemoryLimit()
+
+	if reqCPU == 0 && reqMem == 0 && limCPU == 0 && limMem == 0 {
+		return QoSBestEffort
+	}
+	if limCPU > 0 && limMem > 0 && limCPU == reqCPU && limMem == reqMem {
+		return QoSGuaranteed
+	}
+	return QoSBurstable
+}
+
+// EvictionRank -> lower rank is evicted first
+func (c QoSClass) EvictionRank() int {
+	switch c {
+	case QoSBestEffort:
+		return 0
+	case QoSBurstable:
+		return 1
+	default:
+		return 2
+	}
+}
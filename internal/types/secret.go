@@ -0,0 +1,39 @@
+// Package types. secret.go -> секреты: метаданные (значение никогда не отдаётся через API),
+// ссылки сервиса на секреты (переменная окружения или файл на tmpfs) и секреты задачи.
+package types
+
+import "time"
+
+// DefaultSecretsDir -> directory of secret files in container when target is not set
+const DefaultSecretsDir = "/run/secrets"
+
+// SecretsConfig -> master key of secrets store
+type SecretsConfig struct {
+	KeyFile string `yaml:"key_file" json:"key_file"`                // Base64 key, default "<data_dir>/secrets.key", generated when missing
+	Key     string `yaml:"-" json:"-" env:"GORCHESTER_SECRETS_KEY"` // Base64 key, overrides key_file
+}
+
+// Secret -> metadata of secret, value stays encrypted in DataDir
+type Secret struct {
+	Name      string    `json:"name"`
+	Version   int       `json:"version"` // Incremented on every rotation
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Services  []string  `json:"services,omitempty"` // Services referencing secret
+}
+
+// SecretRef -> secret of service, delivered as env var or as read-only file
+type SecretRef struct {
+	Name   string `yaml:"name" json:"name"`
+	Env    string `yaml:"env,omitempty" json:"env,omitempty"`       // Env var name, empty -> file
+	Target string `yaml:"target,omitempty" json:"target,omitempty"` // File path in container, default /run/secrets/<name>
+}
+
+// TaskSecret -> secret resolved for task, value only lives on the way to runtime
+type TaskSecret struct {
+	Name    string `json:"name"`
+	Version int    `json:"version,omitempty"`
+	Env     string `json:"env,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Value   []byte `json:"-"`
+}
@@ -0,0 +1,230 @@
+// Package types. Селекторы меток узлов для правил размещения.
+// Операторы In, NotIn, Exists, DoesNotExist, Gt, Lt над любыми метками.
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SelectorOperator -> operator of the label selector
+type SelectorOperator string
+
+const (
+	OperatorIn           SelectorOperator = "In"           // label value in Values
+	OperatorNotIn        SelectorOperator = "NotIn"        // label missing or value not in Values
+	OperatorExists       SelectorOperator = "Exists"       // label is set
+	OperatorDoesNotExist SelectorOperator = "DoesNotExist" // label is not set
+	OperatorGt           SelectorOperator = "Gt"           // label value (int) greater than Values[0]
+	OperatorLt           SelectorOperator = "Lt"           // label value (int) less than Values[0]
+)
+
+const (
+	// MaxAffinityWeight -> max weight of the preferred rule
+	MaxAffinityWeight = 100
+)
+
+// ParseSelectorOperator -> case-insensitive operator parsing ("in", "not_in", "NotIn"...)
+func ParseSelectorOperator(op string) (SelectorOperator, error) {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(op))
+
+	switch normalized {
+	case "in":
+		return OperatorIn, nil
+	case "notin":
+		return OperatorNotIn, nil
+	case "exists":
+		return OperatorExists, nil
+	case "doesnotexist", "notexists":
+		return OperatorDoesNotExist, nil
+	case "gt":
+		return OperatorGt, nil
+	case "lt":
+		return OperatorLt, nil
+	default:
+		return "", fmt.Errorf("unknown operator %q: must be one of In, NotIn, Exists, DoesNotExist, Gt, Lt", op)
+	}
+}
+
+// IsPreferred -> rule with weight is soft (preferred), without weight is hard (required)
+func (r AffinityRule) IsPreferred() bool {
+	return r.Weight > 0
+}
+
+// Validate -> check operator, values and weight of the rule
+func (r AffinityRule) Validate() error {
+	if r.Type == "" {
+		return fmt.Errorf("type (label key) is required")
+	}
+
+	op, err := ParseSelectorOperator(r.Operator)
+	if err != nil {
+		return err
+	}
+
+	switch op {
+	case OperatorIn, OperatorNotIn:
+		if len(r.Values) == 0 {
+			return fmt.Errorf("operator %s requires at least one value", op)
+		}
+	case OperatorExists, OperatorDoesNotExist:
+		if len(r.Values) != 0 {
+			return fmt.Errorf("operator %s doesn't accept values", op)
+		}
+	case OperatorGt, OperatorLt:
+		if len(r.Values) != 1 {
+			return fmt.Errorf("operator %s requires exactly one value", op)
+		}
+		if _, err := strconv.ParseInt(r.Values[0], 10, 64); err != nil {
+			return fmt.Errorf("operator %s requires an integer value, got %q", op, r.Values[0])
+		}
+	}
+
+	if r.Weight < 0 || r.Weight > MaxAffinityWeight {
+		return fmt.Errorf("weight must be between 0 and %d", MaxAffinityWeight)
+	}
+
+	return nil
+}
+
+// Matches -> does labels set satisfy the rule
+func (r AffinityRule) Matches(labels map[string]string) bool {
+	op, err := ParseSelectorOperator(r.Operator)
+	if err != nil {
+		return false
+	}
+
+	value, exists := labels[r.Type]
+
+	switch op {
+	case OperatorIn:
+		return exists && containsString(r.Values, value)
+	case OperatorNotIn:
+		return !exists || !containsString(r.Values, value)
+	case OperatorExists:
+		return exists
+	case OperatorDoesNotExist:
+		return !exists
+	case OperatorGt, OperatorLt:
+		if !exists || len(r.Values) != 1 {
+			return false
+		}
+		labelValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		ruleValue, err := strconv.ParseInt(r.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if op == OperatorGt {
+			return labelValue > ruleValue
+		}
+		return labelValue < ruleValue
+	}
+	return false
+}
+
+// MatchesRequired -> labels satisfy all required affinity and anti-affinity rules
+func (c *SchedulingConstraints) MatchesRequired(labels map[string]string) bool {
+	if c == nil {
+		return true
+	}
+
+	for _, rule := range c.Affinity {
+		if !rule.IsPreferred() && !rule.Matches(labels) {
+			return false
+		}
+	}
+	for _, rule := range c.AntiAffinity {
+		if !rule.IsPreferred() && rule.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// PreferenceScore -> sum of weights of matched preferred affinity minus matched preferred anti-affinity
+func (c *SchedulingConstraints) PreferenceScore(labels map[string]string) int {
+	if c == nil {
+		return 0
+	}
+
+	score := 0
+	for _, rule := range c.Affinity {
+		if rule.IsPreferred() && rule.Matches(labels) {
+			score += rule.Weight
+		}
+	}
+	for _, rule := range c.AntiAffinity {
+		if rule.IsPreferred() && rule.Matches(labels) {
+			score -= rule.Weight
+		}
+	}
+	return score
+}
+
+// HasPreferred -> constraints contain at least one preferred rule
+func (c *SchedulingConstraints) HasPreferred() bool {
+	if c == nil {
+		return false
+	}
+
+	for _, rule := range c.Affinity {
+		if rule.IsPreferred() {
+			return true
+		}
+	}
+	for _, rule := range c.AntiAffinity {
+		if rule.IsPreferred() {
+			return true
+		}
+	}
+	for _, rule := range c.ServiceAffinity {
+		if rule.IsPreferred() {
+			return true
+		}
+	}
+	for _, rule := range c.ServiceAntiAffinity {
+		if rule.IsPreferred() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasServiceRules -> constraints refer to placement of other tasks
+func (c *SchedulingConstraints) HasServiceRules() bool {
+	return c != nil && (len(c.ServiceAffinity) > 0 || len(c.ServiceAntiAffinity) > 0)
+}
+
+// IsPreferred -> rule with weight is soft (preferred), without weight is hard (required)
+func (r ServiceAffinityRule) IsPreferred() bool {
+	return r.Weight > 0
+}
+
+// Validate -> check service, topology key and weight of the rule
+func (r ServiceAffinityRule) Validate() error {
+	if r.Service == "" {
+		return fmt.Errorf("service is required")
+	}
+	if r.TopologyKey == "" {
+		return fmt.Errorf("topology_key is required")
+	}
+	if r.Weight < 0 || r.Weight > MaxAffinityWeight {
+		return fmt.Errorf("weight must be between 0 and %d", MaxAffinityWeight)
+	}
+	return nil
+}
+
+// ========= HELPERS =========
+
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+// Package types. snapshot.go -> снимки управляемых томов: tar-архив тома в DataDir/backups,
+// метаданные снимка и политика резервного копирования сервиса (quiesce, расписание, хранение).
+package types
+
+import "time"
+
+// SnapshotStatus -> result of snapshot
+type SnapshotStatus string
+
+const (
+	SnapshotStatusRunning   SnapshotStatus = "running"   // archive is being written
+	SnapshotStatusCompleted SnapshotStatus = "completed" // archive is ready for restore
+	SnapshotStatusFailed    SnapshotStatus = "failed"    // archive was not written, see Error
+)
+
+// BackupPolicy -> how volumes of service are snapshotted
+type BackupPolicy struct {
+	PreSnapshot  []string `yaml:"pre_snapshot,omitempty" json:"pre_snapshot,omitempty"`   // Quiesce command run in every container mounting the volume
+	PostSnapshot []string `yaml:"post_snapshot,omitempty" json:"post_snapshot,omitempty"` // Resume command, runs even when snapshot failed
+	Schedule     string   `yaml:"schedule,omitempty" json:"schedule,omitempty"`           // Cron expression, empty -> manual snapshots only
+	Retention    int      `yaml:"retention,omitempty" json:"retention,omitempty"`         // Scheduled snapshots kept per volume, 0 -> all
+}
+
+// Snapshot -> archive of volume data and where it came from
+type Snapshot struct {
+	ID          string         `json:"id"`
+	Volume      string         `json:"volume"`
+	ServiceName string         `json:"service_name"`
+	NodeID      string         `json:"node_id"`
+	Image       string         `json:"image"` // Helper container image, reused on restore
+	Path        string         `json:"path"`  // Archive in DataDir/backups
+	Size        int64          `json:"size"`  // Archive size in bytes
+	Status      SnapshotStatus `json:"status"`
+	Error       string         `json:"error,omitempty"`
+	Quiesced    bool           `json:"quiesced"`  // Pre-snapshot command ran in containers
+	Scheduled   bool           `json:"scheduled"` // Taken by backup schedule, subject to retention
+	CreatedAt   time.Time      `json:"created_at"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+}
@@ -4,6 +4,7 @@
 package types
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -16,12 +17,13 @@ type TaskStatus string
 
 // Statuses
 const (
-	TaskStatusStarting TaskStatus = "starting" // Starting Container
-	TaskStatusPending  TaskStatus = "pending"  // Container created, but not started
-	TaskStatusRunning  TaskStatus = "running"  // Container running
-	TaskStatusStopped  TaskStatus = "stopped"  // Container stopped
-	TaskStatusFailed   TaskStatus = "failed"   // Error in container running
-	TaskStatusDead     TaskStatus = "dead"     // Container ended
+	TaskStatusStarting TaskStatus = "starting"  // Starting Container
+	TaskStatusPending  TaskStatus = "pending"   // Container created, but not started
+	TaskStatusRunning  TaskStatus = "running"   // Container running
+	TaskStatusStopped  TaskStatus = "stopped"   // Container stopped
+	TaskStatusFailed   TaskStatus = "failed"    // Error in container running
+	TaskStatusDead     TaskStatus = "dead"      // Container ended
+	TaskStatusNodeLost TaskStatus = "node_lost" // Node lost, replaced elsewhere, container may still exist on node
 )
 
 // Task structure
@@ -36,12 +38,19 @@ type Task struct {
 	UpdatedAt     time.Time         `json:"updated_at"`            // Updated timestamp
 	StartedAt     *time.Time        `json:"started_at,omitempty"`  // Task start time
 	FinishedAt    *time.Time        `json:"finished_at,omitempty"` // Task finished time
+	HealthyAt     *time.Time        `json:"healthy_at,omitempty"`  // First passed health check of container
 	ExitCode      int               `json:"exit_code,omitempty"`   // Task exit code
 	Error         string            `json:"err,omitempty"`         // If error occurred
 	RestartCount  int               `json:"restart_counter"`       // Task restart counter
 	PortMapping   []PortMapping     `json:"port_mapping"`          // Task port mapping
+	CPUSet        string            `json:"cpu_set,omitempty"`     // Cores pinned by CPU manager
 	CPUUsage      int64             `json:"cpu_usage"`             // CPU Usage in millicores
 	MemoryUsage   int64             `json:"mem_usage"`             // Memory usage in bytes
+	Ordinal       *int              `json:"ordinal,omitempty"`     // Stable index of stateful replica
+	Hostname      string            `json:"hostname,omitempty"`    // Stable hostname and container name of stateful replica
+	Volumes       []TaskVolume      `json:"volumes,omitempty"`     // Named volumes mounted into container
+	Networks      []TaskNetwork     `json:"networks,omitempty"`    // Networks container is attached to
+	Secrets       []TaskSecret      `json:"secrets,omitempty"`     // Secret references, values are never stored
 	Labels        map[string]string `json:"labels"`                // Meta info
 	ServiceConfig *ServiceConfig    `json:"service_config"`        // Service configuration
 }
@@ -75,6 +84,39 @@ func (t *Task) DeepCopy() *Task {
 		RestartCount: t.RestartCount,
 		CPUUsage:     t.CPUUsage,
 		MemoryUsage:  t.MemoryUsage,
+		CPUSet:       t.CPUSet,
+		Hostname:     t.Hostname,
+	}
+
+	if t.Ordinal != nil {
+		ordinal := *t.Ordinal
+		copy.Ordinal = &ordinal
+	}
+
+	if t.Volumes != nil {
+		copy.Volumes = make([]TaskVolume, len(t.Volumes))
+		for i, v := range t.Volumes {
+			v.DriverOpts = copyStringMap(v.DriverOpts)
+			v.Labels = copyStringMap(v.Labels)
+			copy.Volumes[i] = v
+		}
+	}
+
+	if t.Networks != nil {
+		copy.Networks = make([]TaskNetwork, len(t.Networks))
+		for i, n := range t.Networks {
+			n.Labels = copyStringMap(n.Labels)
+			n.Aliases = append([]string(nil), n.Aliases...)
+			copy.Networks[i] = n
+		}
+	}
+
+	if t.Secrets != nil {
+		copy.Secrets = make([]TaskSecret, len(t.Secrets))
+		for i, sec := range t.Secrets {
+			sec.Value = append([]byte(nil), sec.Value...)
+			copy.Secrets[i] = sec
+		}
 	}
 
 	if t.StartedAt != nil {
@@ -87,6 +129,11 @@ func (t *Task) DeepCopy() *Task {
 		copy.FinishedAt = &finished
 	}
 
+	if t.HealthyAt != nil {
+		healthy := *t.HealthyAt
+		copy.HealthyAt = &healthy
+	}
+
 	if t.PortMapping != nil {
 		copy.PortMapping = make([]PortMapping, len(t.PortMapping))
 		for i, pm := range t.PortMapping {
@@ -109,6 +156,11 @@ func (t *Task) DeepCopy() *Task {
 	return copy
 }
 
+// OrdinalHostname -> "redis-cache-0": stable name of stateful replica
+func OrdinalHostname(serviceName string, ordinal int) string {
+	return fmt.Sprintf("%s-%d", serviceName, ordinal)
+}
+
 // Is task runnung
 func (t *Task) IsRunning() bool {
 	return t.Status == TaskStatusRunning
@@ -118,7 +170,18 @@ func (t *Task) IsRunning() bool {
 func (t *Task) IsTerminated() bool {
 	return t.Status == TaskStatusStopped ||
 		t.Status == TaskStatusFailed ||
-		t.Status == TaskStatusDead
+		t.Status == TaskStatusDead ||
+		t.Status == TaskStatusNodeLost
+}
+
+// Is task placed and expected to run
+func (t *Task) IsActive() bool {
+	if t.DesiredState != TaskStatusRunning {
+		return false
+	}
+	return t.Status == TaskStatusRunning ||
+		t.Status == TaskStatusPending ||
+		t.Status == TaskStatusStarting
 }
 
 // Is task needs restart
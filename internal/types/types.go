@@ -38,7 +38,14 @@ type ResourceRequirements struct {
 	CPUMilliCores int64  `yaml:"cpu_millicores" json:"cpu_millicores"`
 	MemoryBytes   int64  `yaml:"memory_bytes" json:"memory_bytes"`
 	DiskBytes     int64  `yaml:"disk_bytes" json:"disk_bytes"`
-	CPUSet        string `yaml:"cpu_set" json:"cpu_set"` // Example: "0-3", "0,1"
+	CPUSet        string `yaml:"cpu_set" json:"cpu_set"`               // Example: "0-3", "0,1"
+	ExclusiveCPUs int    `yaml:"exclusive_cpus" json:"exclusive_cpus"` // Whole cores pinned by CPU manager
+
+	Extended map[string]int64 `yaml:"extended_resources,omitempty" json:"extended_resources,omitempty"` // Countable resources: "licence-seat" -> 1
+
+	// Separate requests / limits, flat cpu_millicores / memory_bytes are both when not set
+	Requests *ResourceList `yaml:"requests,omitempty" json:"requests,omitempty"` // Used by scheduler
+	Limits   *ResourceList `yaml:"limits,omitempty" json:"limits,omitempty"`     // Enforced by Docker
 }
 
 // ScalePolicy -> policy for auto-scaling
@@ -82,6 +89,50 @@ type OchestratorConfig struct {
 	ClusterName string          `yaml:"cluster_name" env-default:"default-name"`    // Name of the Cluster
 	Services    []ServiceConfig `yaml:"services"`                                   // Services for orchestration
 	Nodes       []NodeConfig    `yaml:"nodes"`                                      // Nodes from cfg
+	Volumes     []VolumeConfig  `yaml:"volumes"`                                    // Managed named volumes
+	Networks    []NetworkConfig `yaml:"networks"`                                   // Managed networks
+	DNS         DNSConfig       `yaml:"dns"`                                        // Service discovery DNS server
+	Ingress     IngressConfig   `yaml:"ingress"`                                    // Load balancer in front of replicas
+	Secrets     SecretsConfig   `yaml:"secrets"`                                    // Encrypted secrets store
+
+	HostPortRange PortRange        `yaml:"host_port_range"` // Range for dynamic host ports (host_port: 0)
+	Rebalancer    RebalancerConfig `yaml:"rebalancer"`      // Background tasks rebalancing
+
+	Overcommit              OvercommitRatios `yaml:"overcommit"`                // Default capacity multipliers for nodes
+	MemoryPressureThreshold float64          `yaml:"memory_pressure_threshold"` // % of node memory in use -> QoS eviction
+
+	NodeLostGracePeriod time.Duration `yaml:"node_lost_grace_period"` // NotReady node -> its tasks are lost and replaced after this
+	DrainTimeout        time.Duration `yaml:"drain_timeout"`          // Default limit for node drain
+
+	JoinToken  string `yaml:"join_token" env:"GORCHESTER_JOIN_TOKEN"`   // Token for node registration API, empty -> generated
+	AdminToken string `yaml:"admin_token" env:"GORCHESTER_ADMIN_TOKEN"` // Token for secrets API, empty -> generated
+}
+
+// RebalancerConfig -> descheduler settings
+type RebalancerConfig struct {
+	Enabled          bool          `yaml:"enabled" json:"enabled"`                         // Start background rebalancer
+	DryRun           bool          `yaml:"dry_run" json:"dry_run"`                         // Only log intended moves
+	Interval         time.Duration `yaml:"interval" json:"interval"`                       // How often to look for imbalance
+	MaxMovesPerCycle int           `yaml:"max_moves_per_cycle" json:"max_moves_per_cycle"` // Max migrations started in one cycle
+	MaxNodeSkew      int           `yaml:"max_node_skew" json:"max_node_skew"`             // Allowed replicas difference between nodes
+	ReadyTimeout     time.Duration `yaml:"ready_timeout" json:"ready_timeout"`             // How long to wait for the surge task
+}
+
+// DNSConfig -> embedded DNS server answering for <service>.<cluster_name>.<domain>
+type DNSConfig struct {
+	Enabled     bool          `yaml:"enabled" json:"enabled"`
+	ListenAddr  string        `yaml:"listen_addr" json:"listen_addr"`   // UDP and TCP address
+	Domain      string        `yaml:"domain" json:"domain"`             // Zone suffix after cluster name
+	TTL         time.Duration `yaml:"ttl" json:"ttl"`                   // TTL of answers, short: tasks come and go
+	Inject      bool          `yaml:"inject" json:"inject"`             // Add advertise_ip to dns of every service
+	AdvertiseIP string        `yaml:"advertise_ip" json:"advertise_ip"` // Address containers reach the server on
+	Upstream    []string      `yaml:"upstream" json:"upstream"`         // Resolvers for names outside zone, empty -> refused
+}
+
+// PortRange -> inclusive range of host ports
+type PortRange struct {
+	Start int `yaml:"start" json:"start"`
+	End   int `yaml:"end" json:"end"`
 }
 
 // Service config struct
@@ -91,20 +142,30 @@ type ServiceConfig struct {
 	Replicas      int           `yaml:"replicas" json:"replicas"`
 	Ports         []PortMapping `yaml:"ports" json:"ports"`
 	Env           []string      `yaml:"env" json:"env"`
+	Secrets       []SecretRef   `yaml:"secrets" json:"secrets"` // From secrets store, as env or file
 	Command       []string      `yaml:"command" json:"command"`
 	Volumes       []string      `yaml:"volumes" json:"volumes"`
-	Network       string        `yaml:"network" json:"network"`
+	Network       string        `yaml:"network" json:"network"`   // Single network, same as networks: [name]
+	Networks      []string      `yaml:"networks" json:"networks"` // Top level networks, empty -> own isolated network
 	NetworkMode   string        `yaml:"network_mode" json:"network_mode"`
 	DNS           []string      `yaml:"dns" json:"dns"`
 	ExtraHosts    []string      `yaml:"extra_hosts" json:"extra_hosts"`
 	RestartPolicy string        `yaml:"restart_policy" json:"restart_policy"`
 
-	ServiceType           ServiceType            `yaml:"service_type" json:"service_type"`
-	SchedulingConstraints *SchedulingConstraints `yaml:"scheduling_constraints,omitempty" json:"scheduling_constraints,omitempty"`
+	ServiceType           ServiceType                `yaml:"service_type" json:"service_type"`
+	SchedulingConstraints *SchedulingConstraints     `yaml:"scheduling_constraints,omitempty" json:"scheduling_constraints,omitempty"`
+	TopologySpread        []TopologySpreadConstraint `yaml:"topology_spread,omitempty" json:"topology_spread,omitempty"`
 
 	Resources   ResourceRequirements `yaml:"resources"`    // Resources for service
 	ScalePolicy ScalePolicy          `yaml:"scale_policy"` // Scaling policy
 	HealthCheck *HealthCheck         `yaml:"health_check"` // Health checking
+
+	DisruptionBudget DisruptionBudget `yaml:"disruption_budget" json:"disruption_budget"` // Voluntary disruptions
+
+	Mounts              []VolumeMount         `yaml:"mounts,omitempty" json:"mounts,omitempty"`               // Named volumes from top level
+	VolumeClaims        []VolumeClaimTemplate `yaml:"volume_claims,omitempty" json:"volume_claims,omitempty"` // Volume per stateful replica
+	VolumeReclaimPolicy VolumeReclaimPolicy   `yaml:"volume_reclaim_policy" json:"volume_reclaim_policy"`     // Claim volumes after service removal
+	Backup              *BackupPolicy         `yaml:"backup,omitempty" json:"backup,omitempty"`               // Volume snapshots
 }
 
 // ExecConfig struct -> run in Container for HealthCheck
@@ -116,14 +177,41 @@ type ExecConfig struct {
 
 // Scheduling data structs rules
 type SchedulingConstraints struct {
-	Affinity     []AffinityRule `yaml:"affinity,omitempty" json:"affinity,omitempty"`
-	AntiAffinity []AffinityRule `yaml:"anti_affinity,omitempty" json:"anti_affinity,omitempty"`
+	Affinity     []AffinityRule `yaml:"affinity,omitempty" json:"affinity,omitempty"`           // Node must (or should, with weight) match
+	AntiAffinity []AffinityRule `yaml:"anti_affinity,omitempty" json:"anti_affinity,omitempty"` // Node must not (or should not, with weight) match
+
+	ServiceAffinity     []ServiceAffinityRule `yaml:"service_affinity,omitempty" json:"service_affinity,omitempty"`           // Co-locate with tasks of the service
+	ServiceAntiAffinity []ServiceAffinityRule `yaml:"service_anti_affinity,omitempty" json:"service_anti_affinity,omitempty"` // Separate from tasks of the service
 }
 
+// AffinityRule -> label selector over node labels
 type AffinityRule struct {
-	Type     string   `yaml:"type" json:"type"`
-	Operator string   `yaml:"operator" json:"operator"`
-	Values   []string `yaml:"values,omitempty" json:"values,omitempty"`
+	Type     string   `yaml:"type" json:"type"`                         // Label key: any node label ("zone", "disk", "gpu"...)
+	Operator string   `yaml:"operator" json:"operator"`                 // In, NotIn, Exists, DoesNotExist, Gt, Lt
+	Values   []string `yaml:"values,omitempty" json:"values,omitempty"` // Label values
+	Weight   int      `yaml:"weight,omitempty" json:"weight,omitempty"` // 0 = required, 1-100 = preferred
+}
+
+// ServiceAffinityRule -> placement relative to tasks of another service (or the service itself)
+type ServiceAffinityRule struct {
+	Service     string `yaml:"service" json:"service"`                   // Service name (may be the service itself)
+	TopologyKey string `yaml:"topology_key" json:"topology_key"`         // "node" or any node label ("zone", "rack"...)
+	Weight      int    `yaml:"weight,omitempty" json:"weight,omitempty"` // 0 = required, 1-100 = preferred
+}
+
+// WhenUnsatisfiable -> what to do when topology spread can't be satisfied
+type WhenUnsatisfiable string
+
+const (
+	DoNotSchedule  WhenUnsatisfiable = "DoNotSchedule"  // node is filtered out
+	ScheduleAnyway WhenUnsatisfiable = "ScheduleAnyway" // least skewed domains are preferred
+)
+
+// TopologySpreadConstraint -> max difference of service replicas between topology domains
+type TopologySpreadConstraint struct {
+	TopologyKey       string            `yaml:"topology_key" json:"topology_key"`             // "node" or any node label
+	MaxSkew           int               `yaml:"max_skew" json:"max_skew"`                     // Max replicas difference between domains
+	WhenUnsatisfiable WhenUnsatisfiable `yaml:"when_unsatisfiable" json:"when_unsatisfiable"` // DoNotSchedule / ScheduleAnyway
 }
 
 // PredictiveScalingConfig for predictive scheduling
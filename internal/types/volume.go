@@ -0,0 +1,116 @@
+// Package types. volume.go -> именованные тома Docker: объявления в конфиге,
+// шаблоны томов для реплик stateful-сервисов и записи о томах с привязкой к узлу.
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultVolumeDriver -> volume lives on the node it was created on
+const DefaultVolumeDriver = "local"
+
+// VolumeReclaimPolicy -> what happens to claim volumes when their service is removed
+type VolumeReclaimPolicy string
+
+const (
+	VolumeReclaimRetain VolumeReclaimPolicy = "retain" // volume is kept, can be deleted by hand
+	VolumeReclaimDelete VolumeReclaimPolicy = "delete" // volume is removed from its node
+)
+
+// VolumeStatus -> lifecycle of tracked volume
+type VolumeStatus string
+
+const (
+	VolumeStatusPending  VolumeStatus = "pending"  // declared, not created on any node yet
+	VolumeStatusBound    VolumeStatus = "bound"    // created, local volume is bound to NodeID
+	VolumeStatusReleased VolumeStatus = "released" // owner service removed, volume retained
+)
+
+// VolumeConfig -> named volume declared at top level of config
+type VolumeConfig struct {
+	Name       string            `yaml:"name" json:"name"`
+	Driver     string            `yaml:"driver" json:"driver"`                               // Default "local"
+	DriverOpts map[string]string `yaml:"driver_opts,omitempty" json:"driver_opts,omitempty"` // Passed to volume driver
+	Labels     map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+// VolumeMount -> named volume mounted into service containers
+type VolumeMount struct {
+	Volume   string `yaml:"volume" json:"volume"` // Name from top level volumes
+	Target   string `yaml:"target" json:"target"` // Absolute path in container
+	ReadOnly bool   `yaml:"read_only" json:"read_only"`
+}
+
+// VolumeClaimTemplate -> one volume per stateful replica, named "<name>-<service>-<ordinal>"
+type VolumeClaimTemplate struct {
+	Name       string            `yaml:"name" json:"name"`
+	Target     string            `yaml:"target" json:"target"`
+	ReadOnly   bool              `yaml:"read_only" json:"read_only"`
+	Driver     string            `yaml:"driver" json:"driver"` // Default "local"
+	DriverOpts map[string]string `yaml:"driver_opts,omitempty" json:"driver_opts,omitempty"`
+}
+
+// Volume -> tracked volume and its node binding
+type Volume struct {
+	Name       string            `json:"name"`
+	Driver     string            `json:"driver"`
+	DriverOpts map[string]string `json:"driver_opts,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Status     VolumeStatus      `json:"status"`
+	NodeID     string            `json:"node_id,omitempty"` // Node holding local volume
+	CreatedAt  time.Time         `json:"created_at"`
+
+	// Claim volumes only
+	ServiceName   string              `json:"service_name,omitempty"`
+	Claim         string              `json:"claim,omitempty"`
+	Ordinal       *int                `json:"ordinal,omitempty"`
+	ReclaimPolicy VolumeReclaimPolicy `json:"reclaim_policy,omitempty"`
+}
+
+// TaskVolume -> volume resolved for task: mount point and where the volume lives
+type TaskVolume struct {
+	Name       string            `json:"name"`
+	Target     string            `json:"target"`
+	ReadOnly   bool              `json:"read_only,omitempty"`
+	Driver     string            `json:"driver"`
+	DriverOpts map[string]string `json:"driver_opts,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	NodeID     string            `json:"node_id,omitempty"` // Task must run here, empty -> any node
+}
+
+// ClaimVolumeName -> "data-redis-cache-0"
+func ClaimVolumeName(claim, serviceName string, ordinal int) string {
+	return fmt.Sprintf("%s-%s-%d", claim, serviceName, ordinal)
+}
+
+// NodeBound -> local volumes exist on one node only
+func (v *Volume) NodeBound() bool {
+	return v.Driver == "" || v.Driver == DefaultVolumeDriver
+}
+
+// DeepCopy -> copy of volume record
+func (v *Volume) DeepCopy() *Volume {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	c.DriverOpts = copyStringMap(v.DriverOpts)
+	c.Labels = copyStringMap(v.Labels)
+	if v.Ordinal != nil {
+		ordinal := *v.Ordinal
+		c.Ordinal = &ordinal
+	}
+	return &c
+}
+
+func copyStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}